@@ -0,0 +1,237 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/chaosblade-io/chaosblade-operator/pkg/agent"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/auth"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/runtime/chaosblade"
+)
+
+// agentClient is the https client of the agents, authenticated by the token in the agent auth secret
+type agentClient struct {
+	client *http.Client
+	token  string
+}
+
+var agentClientCache struct {
+	lock   sync.Mutex
+	client *agentClient
+}
+
+// agentExecute executes the command in the pod by the agent on the node of the pod. The command runs
+// in the agent container directly if the pod is the chaosblade tool pod. The pods are read from the cache
+// of the manager, so the agent channel does not request the apiserver for each command.
+func (c *Client) agentExecute(ctx context.Context, options *ExecOptions, streamOptions remotecommand.StreamOptions) error {
+	pod := &corev1.Pod{}
+	if err := c.Client.Get(ctx, types.NamespacedName{Namespace: options.PodNamespace, Name: options.PodName}, pod); err != nil {
+		return err
+	}
+	var err error
+	agentPod, containerId := pod, ""
+	if !isAgentPod(pod) {
+		if containerId, err = getContainerId(pod, options.ContainerName); err != nil {
+			return err
+		}
+		if agentPod, err = c.getAgentPod(ctx, pod.Spec.NodeName); err != nil {
+			return err
+		}
+	}
+	client, err := c.getAgentClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{agent.CommandParam: options.Command}
+	if containerId != "" {
+		query.Set(agent.ContainerIdParam, containerId)
+	}
+	var body io.Reader
+	if streamOptions.Stdin != nil {
+		query.Set(agent.StdinParam, "true")
+		body = streamOptions.Stdin
+	}
+	host := net.JoinHostPort(agentPod.Status.PodIP, strconv.Itoa(AgentPort))
+	requestURL := url.URL{Scheme: "https", Host: host, Path: agent.ExecPath, RawQuery: query.Encode()}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL.String(), body)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", auth.BearerPrefix+client.token)
+	response, err := client.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		if response.StatusCode == http.StatusUnauthorized {
+			// the agent auth secret may be rotated, reload the client by the next execution
+			invalidateAgentClient(client)
+		}
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 4096))
+		return &agentResponseError{host: host, code: response.StatusCode, message: strings.TrimSpace(string(message))}
+	}
+	return readFrames(response.Body, streamOptions)
+}
+
+// readFrames writes the output frames to the streams until the last frame
+func readFrames(reader io.Reader, streamOptions remotecommand.StreamOptions) error {
+	decoder := json.NewDecoder(reader)
+	for {
+		frame := &agent.Frame{}
+		if err := decoder.Decode(frame); err != nil {
+			if err == io.EOF {
+				return errors.New("agent closed the stream before the command exited")
+			}
+			return err
+		}
+		switch {
+		case frame.Error != "":
+			return errors.New(frame.Error)
+		case frame.ExitCode != nil:
+			if *frame.ExitCode == 0 {
+				return nil
+			}
+			return utilexec.CodeExitError{
+				Err:  fmt.Errorf("command terminated with exit code %d", *frame.ExitCode),
+				Code: *frame.ExitCode,
+			}
+		case frame.Stream == agent.StdoutStream:
+			streamOptions.Stdout.Write(frame.Data)
+		case frame.Stream == agent.StderrStream:
+			streamOptions.Stderr.Write(frame.Data)
+		}
+	}
+}
+
+// getAgentClient returns the cached agent client, which is created by the agent auth secret. The secret is
+// read only if the client is not cached, the client is removed from the cache by the unauthorized response.
+func (c *Client) getAgentClient(ctx context.Context) (*agentClient, error) {
+	agentClientCache.lock.Lock()
+	defer agentClientCache.lock.Unlock()
+	if agentClientCache.client != nil {
+		return agentClientCache.client, nil
+	}
+	secret, err := c.CoreV1().Secrets(chaosblade.DaemonsetPodNamespace).Get(ctx, agent.AuthSecretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get agent auth secret error, %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(secret.Data[agent.AuthCAKey]) {
+		return nil, errors.New("invalid ca certificate of the agent")
+	}
+	agentClientCache.client = &agentClient{
+		token: strings.TrimSpace(string(secret.Data[agent.AuthTokenKey])),
+		client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: (&net.Dialer{
+					Timeout: 5 * time.Second,
+				}).DialContext,
+				TLSClientConfig: &tls.Config{
+					RootCAs:    pool,
+					ServerName: agent.AuthServerName,
+					MinVersion: tls.VersionTLS12,
+				},
+			},
+		},
+	}
+	return agentClientCache.client, nil
+}
+
+// invalidateAgentClient removes the client from the cache if it is not replaced yet
+func invalidateAgentClient(client *agentClient) {
+	agentClientCache.lock.Lock()
+	defer agentClientCache.lock.Unlock()
+	if agentClientCache.client == client {
+		agentClientCache.client = nil
+	}
+}
+
+// getAgentPod returns the running chaosblade tool pod on the node
+func (c *Client) getAgentPod(ctx context.Context, nodeName string) (*corev1.Pod, error) {
+	podList := &corev1.PodList{}
+	if err := c.Client.List(ctx, podList, client.InNamespace(chaosblade.DaemonsetPodNamespace),
+		client.MatchingLabels(chaosblade.DaemonsetPodLabels)); err != nil {
+		return nil, err
+	}
+	for i := range podList.Items {
+		if podList.Items[i].Spec.NodeName == nodeName &&
+			podList.Items[i].Status.Phase == corev1.PodRunning && podList.Items[i].Status.PodIP != "" {
+			return &podList.Items[i], nil
+		}
+	}
+	return nil, fmt.Errorf("running chaosblade tool pod not found on %s node", nodeName)
+}
+
+func isAgentPod(pod *corev1.Pod) bool {
+	return pod.Namespace == chaosblade.DaemonsetPodNamespace &&
+		labels.SelectorFromSet(chaosblade.DaemonsetPodLabels).Matches(labels.Set(pod.Labels))
+}
+
+// getContainerId returns the id without the runtime prefix of the container, the first container by default
+func getContainerId(pod *corev1.Pod, containerName string) (string, error) {
+	if containerName == "" && len(pod.Spec.Containers) > 0 {
+		containerName = pod.Spec.Containers[0].Name
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != containerName {
+			continue
+		}
+		if status.ContainerID == "" {
+			return "", fmt.Errorf("container %s of pods/%s is not started", containerName, pod.Name)
+		}
+		if index := strings.Index(status.ContainerID, "://"); index >= 0 {
+			return status.ContainerID[index+3:], nil
+		}
+		return status.ContainerID, nil
+	}
+	return "", fmt.Errorf("container %s not found in pods/%s", containerName, pod.Name)
+}
+
+// agentResponseError is the error response of the agent
+type agentResponseError struct {
+	host    string
+	code    int
+	message string
+}
+
+func (e *agentResponseError) Error() string {
+	return fmt.Sprintf("agent %s response %d, %s", e.host, e.code, e.message)
+}
@@ -17,19 +17,16 @@
 package channel
 
 import (
-	"bytes"
+	"context"
 	"fmt"
 	"io"
-	"net/url"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/remotecommand"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -76,10 +73,14 @@ type ExecOptions struct {
 	ContainerName string
 	Command       []string
 	IgnoreOutput  bool
+	// Timeout is the deadline of the call, ExecTimeout is used if 0
+	Timeout time.Duration
+	// MaxOutputBytes is the max bytes of the stdout and stderr kept in the result, ExecMaxOutputBytes is used if 0
+	MaxOutputBytes int
 }
 
-// Exec command in pod
-func (c *Client) Exec(options *ExecOptions) interface{} {
+// Exec command in pod until it exits or the context is done, the output is decoded by the decoders of the options
+func (c *Client) Exec(ctx context.Context, options *ExecOptions) interface{} {
 	logFields := logrus.WithFields(logrus.Fields{
 		"command":      options.Command,
 		"podName":      options.PodName,
@@ -87,35 +88,19 @@ func (c *Client) Exec(options *ExecOptions) interface{} {
 		"container":    options.ContainerName,
 	})
 	logFields.Infof("Exec command in pod")
-	request := c.CoreV1().RESTClient().Post().
-		Resource("pods").
-		Name(options.PodName).
-		Namespace(options.PodNamespace).
-		SubResource("exec").
-		VersionedParams(
-			&corev1.PodExecOptions{
-				Container: options.ContainerName,
-				Command:   options.Command,
-				Stdin:     options.Stdin,
-				Stdout:    true,
-				Stderr:    true,
-				TTY:       options.TTY,
-			}, scheme.ParameterCodec)
-	output := bytes.NewBuffer([]byte{})
-	options.Out = output
-	errput := bytes.NewBuffer([]byte{})
-	options.ErrOut = errput
-
-	err := execute("POST", request.URL(), c.Config, options)
-	errMsg := strings.TrimSpace(errput.String())
-	outMsg := strings.TrimSpace(output.String())
+	result, err := c.ExecContext(ctx, options)
+	if err == nil && result.ExitCode != 0 {
+		err = fmt.Errorf("command terminated with exit code %d", result.ExitCode)
+	}
+	errMsg := strings.TrimSpace(string(result.Stderr))
+	outMsg := strings.TrimSpace(string(result.Stdout))
 	execLog := logFields.WithFields(logrus.Fields{
 		"err": errMsg,
 		"out": outMsg,
 	})
 	if errMsg != "" {
 		execLog.Infof("get err message")
-		return options.ErrDecoder(errput.Bytes())
+		return options.ErrDecoder(result.Stderr)
 	}
 	if err != nil {
 		execLog.WithError(err).Errorln("Invoke exec command error")
@@ -123,7 +108,7 @@ func (c *Client) Exec(options *ExecOptions) interface{} {
 	}
 	if outMsg != "" {
 		execLog.Infof("get output message")
-		return options.OutDecoder(output.Bytes())
+		return options.OutDecoder(result.Stdout)
 	}
 	if options.IgnoreOutput {
 		return nil
@@ -131,18 +116,3 @@ func (c *Client) Exec(options *ExecOptions) interface{} {
 	return options.ErrDecoder([]byte(fmt.Sprintf("cannot get output of pods/%s/exec, maybe kubelet cannot be accessed or container not found",
 		options.PodName)))
 }
-
-// "172.21.1.11:8080/api/v1/namespaces/default/pods/my-nginx-3855515330-l1uqk/exec
-// ?container=my-nginx&stdin=1&stdout=1&stderr=1&tty=1&command=%2Fbin%2Fbash"
-func execute(method string, url *url.URL, config *rest.Config, options *ExecOptions) error {
-	exec, err := remotecommand.NewSPDYExecutor(config, method, url)
-	if err != nil {
-		return err
-	}
-	return exec.Stream(remotecommand.StreamOptions{
-		Stdin:  options.StreamOptions.In,
-		Stdout: options.StreamOptions.Out,
-		Stderr: options.StreamOptions.ErrOut,
-		Tty:    options.StreamOptions.TTY,
-	})
-}
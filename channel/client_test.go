@@ -17,10 +17,314 @@
 package channel
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/chaosblade-io/chaosblade-operator/pkg/agent"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/runtime/chaosblade"
 )
 
 func TestClient_Exec(t *testing.T) {
 	// Skip this test as it requires a real Kubernetes cluster connection
 	t.Skip("Skipping TestClient_Exec: requires Kubernetes cluster connectivity")
 }
+
+func TestClient_ExecCanceled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	// the abandoned stream holds the connection, release the handler before closing the server
+	defer close(release)
+
+	config := &rest.Config{Host: server.URL}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		t.Fatalf("create clientset failed, %v", err)
+	}
+	client := &Client{Interface: clientset, Config: config}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	result := client.Exec(ctx, &ExecOptions{
+		StreamOptions: StreamOptions{
+			ErrDecoder: func(bytes []byte) interface{} { return string(bytes) },
+			OutDecoder: func(bytes []byte) interface{} { return string(bytes) },
+		},
+		PodName:      "pod",
+		PodNamespace: "default",
+		Command:      []string{"ls"},
+		Timeout:      time.Minute,
+	})
+	if result == nil {
+		t.Errorf("expected the error of the canceled exec")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("expected the exec canceled with the context, elapsed %s", elapsed)
+	}
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	buffer := &limitedBuffer{limit: 5}
+	for _, p := range []string{"abc", "def", "ghi"} {
+		if n, err := buffer.Write([]byte(p)); n != len(p) || err != nil {
+			t.Fatalf("unexpected write result %d, %v", n, err)
+		}
+	}
+	if buffer.String() != "abcde" || !buffer.truncated {
+		t.Errorf("unexpected buffer %s, truncated: %t", buffer.String(), buffer.truncated)
+	}
+}
+
+func TestExecResult_Err(t *testing.T) {
+	if err := (&ExecResult{Stderr: []byte("warning")}).Err(); err != nil {
+		t.Errorf("unexpected error of the zero exit code, %v", err)
+	}
+	err := (&ExecResult{ExitCode: 1, Stderr: []byte("not found\n")}).Err()
+	if err == nil || err.Error() != "command terminated with exit code 1, not found" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestClient_ExecContextFallback(t *testing.T) {
+	var lock sync.Mutex
+	methods := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		methods = append(methods, r.Method)
+		lock.Unlock()
+		http.Error(w, "upgrade not supported", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	config := &rest.Config{Host: server.URL}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		t.Fatalf("create clientset failed, %v", err)
+	}
+	client := &Client{Interface: clientset, Config: config}
+	ExecProtocol = WebSocketProtocol
+	defer func() { ExecProtocol = SPDYProtocol }()
+	if _, err := client.ExecContext(context.Background(), &ExecOptions{
+		PodName:      "pod",
+		PodNamespace: "default",
+		Command:      []string{"ls"},
+	}); err == nil {
+		t.Fatalf("expected the exec failed")
+	}
+	if len(methods) != 2 || methods[0] != http.MethodGet || methods[1] != http.MethodPost {
+		t.Errorf("expected the websocket request falls back to spdy, got %v", methods)
+	}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		err       error
+		retryable bool
+	}{
+		{nil, false},
+		{errors.New("error dialing backend: dial tcp 10.0.0.1:10250: connect: connection refused"), true},
+		{errors.New("read tcp 10.0.0.1:443: connection reset by peer"), false},
+		{errors.New("write tcp 10.0.0.1:443: broken pipe"), false},
+		{errors.New("http2: client connection lost"), false},
+		{errors.New("agent closed the stream before the command exited"), false},
+		{io.ErrUnexpectedEOF, false},
+		{fmt.Errorf("read stream failed, %w", io.EOF), false},
+		{errors.New(`container not found ("nginx")`), true},
+		{&agentResponseError{code: http.StatusBadGateway}, true},
+		{&agentResponseError{code: http.StatusUnauthorized}, false},
+		{context.DeadlineExceeded, false},
+		{errors.New("pods \"nginx\" not found"), false},
+	}
+	for _, tt := range tests {
+		if retryable := IsRetryableError(tt.err); retryable != tt.retryable {
+			t.Errorf("IsRetryableError(%v) = %t, want %t", tt.err, retryable, tt.retryable)
+		}
+	}
+	if !isRetryableResult(&ExecResult{ExitCode: 126, Stderr: []byte("OCI runtime exec failed: container not running")}) {
+		t.Errorf("expected the result of the container not running is retryable")
+	}
+	if isRetryableResult(&ExecResult{ExitCode: 1, Stdout: []byte(`{"code":1}`), Stderr: []byte("container not found")}) {
+		t.Errorf("expected the result with the output is not retryable")
+	}
+}
+
+func TestClient_ExecContextWithRetry(t *testing.T) {
+	var lock sync.Mutex
+	requests := 0
+	code, message := http.StatusInternalServerError, "error dialing backend: connection reset by peer"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requests++
+		lock.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		fmt.Fprintf(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","message":%q,"code":%d}`, message, code)
+	}))
+	defer server.Close()
+
+	config := &rest.Config{Host: server.URL}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		t.Fatalf("create clientset failed, %v", err)
+	}
+	client := &Client{Interface: clientset, Config: config}
+	ExecProtocol = SPDYProtocol
+	options := &ExecOptions{PodName: "pod", PodNamespace: "default", Command: []string{"ls"}}
+	policy := RetryPolicy{Attempts: 3, Backoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	if _, attempts, err := client.ExecContextWithRetry(context.Background(), options, policy); err == nil || attempts != 3 || requests != 3 {
+		t.Errorf("expected 3 attempts of the transient error, got %d attempts, %d requests, %v", attempts, requests, err)
+	}
+
+	requests = 0
+	code, message = http.StatusNotFound, `pods "pod" not found`
+	if _, attempts, err := client.ExecContextWithRetry(context.Background(), options, policy); err == nil || attempts != 1 || requests != 1 {
+		t.Errorf("expected 1 attempt of the fatal error, got %d attempts, %d requests, %v", attempts, requests, err)
+	}
+}
+
+func TestWithRateLimiter(t *testing.T) {
+	if err := waitRateLimiter(WithRateLimiter(context.Background(), 0, 1)); err != nil {
+		t.Errorf("expected no limit if the qps is 0, %v", err)
+	}
+	ctx := WithRateLimiter(context.Background(), 20, 1)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := waitRateLimiter(ctx); err != nil {
+			t.Fatalf("wait the rate limiter failed, %v", err)
+		}
+	}
+	// the first call takes the burst token, the others wait 50ms each
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("expected the calls limited by the qps, elapsed %s", elapsed)
+	}
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := waitRateLimiter(canceled); err == nil {
+		t.Errorf("expected the wait of the canceled context failed")
+	}
+}
+
+func TestWaitRateLimiter_Global(t *testing.T) {
+	defer func(limiter func() flowcontrol.RateLimiter) { globalRateLimiter = limiter }(globalRateLimiter)
+	global := newRateLimiter(20, 1)
+	globalRateLimiter = func() flowcontrol.RateLimiter { return global }
+
+	// the experiments without their own limit still share the operator-wide limit
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := waitRateLimiter(context.Background()); err != nil {
+			t.Fatalf("wait the rate limiter failed, %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("expected the calls limited by the global qps, elapsed %s", elapsed)
+	}
+	globalRateLimiter = func() flowcontrol.RateLimiter { return nil }
+	if err := waitRateLimiter(context.Background()); err != nil {
+		t.Errorf("expected no limit if the global qps is 0, %v", err)
+	}
+}
+
+func TestClient_ExecContextByAgent(t *testing.T) {
+	// the pods are read from the cache, the secret from the apiserver
+	clientset := fake.NewSimpleClientset()
+	cached := ctrlfake.NewClientBuilder().WithObjects(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "chaosblade-tool-abcde",
+			Namespace: chaosblade.DaemonsetPodNamespace,
+			Labels:    chaosblade.DaemonsetPodLabels,
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "127.0.0.1"},
+	}).Build()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := agent.EnsureAuthSecret(ctx, clientset, chaosblade.DaemonsetPodNamespace); err != nil {
+		t.Fatalf("ensure agent auth secret failed, %v", err)
+	}
+	secret, _ := clientset.CoreV1().Secrets(chaosblade.DaemonsetPodNamespace).Get(ctx, agent.AuthSecretName, metav1.GetOptions{})
+	authDir := t.TempDir()
+	for key, value := range secret.Data {
+		os.WriteFile(path.Join(authDir, key), value, 0600)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	go agent.NewServer(fmt.Sprintf("127.0.0.1:%d", port), authDir, "nsexec").Start(ctx)
+	for i := 0; i < 50; i++ {
+		if conn, err := tls.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", port), &tls.Config{InsecureSkipVerify: true}); err == nil {
+			conn.Close()
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	ExecChannel, AgentPort = AgentChannel, port
+	defer func() {
+		ExecChannel, AgentPort = PodsExecChannel, agent.DefaultPort
+		agentClientCache.client = nil
+	}()
+	client := &Client{Interface: clientset, Client: cached}
+	result, err := client.ExecContext(ctx, &ExecOptions{
+		StreamOptions: StreamOptions{
+			IOStreams: IOStreams{In: strings.NewReader("input")},
+			Stdin:     true,
+		},
+		PodName:       "chaosblade-tool-abcde",
+		PodNamespace:  chaosblade.DaemonsetPodNamespace,
+		ContainerName: chaosblade.DaemonsetPodName,
+		Command:       []string{"sh", "-c", "cat; exit 2"},
+	})
+	if err != nil {
+		t.Fatalf("exec by agent failed, %v", err)
+	}
+	if string(result.Stdout) != "input" || result.ExitCode != 2 {
+		t.Errorf("unexpected result %s, exit code %d", result.Stdout, result.ExitCode)
+	}
+
+	agentClientCache.client.token = "invalid"
+	if _, err := client.ExecContext(ctx, &ExecOptions{
+		PodName:      "chaosblade-tool-abcde",
+		PodNamespace: chaosblade.DaemonsetPodNamespace,
+		Command:      []string{"true"},
+	}); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected the unauthorized request rejected, %v", err)
+	}
+	if agentClientCache.client != nil {
+		t.Errorf("expected the agent client invalidated by the unauthorized response")
+	}
+	if _, err := client.ExecContext(ctx, &ExecOptions{
+		PodName:      "chaosblade-tool-abcde",
+		PodNamespace: chaosblade.DaemonsetPodNamespace,
+		Command:      []string{"true"},
+	}); err != nil {
+		t.Errorf("expected the agent client reloaded from the secret, %v", err)
+	}
+}
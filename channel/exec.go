@@ -0,0 +1,272 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+
+	"github.com/chaosblade-io/chaosblade-operator/pkg/agent"
+)
+
+var (
+	// ExecTimeout is the default deadline of the exec calls without a deadline
+	ExecTimeout time.Duration
+	// ExecDeployTimeout is the deadline of the exec calls deploying the chaosblade tool, which copy
+	// or download much more than the commands of the experiments
+	ExecDeployTimeout time.Duration
+	// ExecMaxOutputBytes is the default max bytes of the stdout and stderr kept in the exec result
+	ExecMaxOutputBytes int
+	// ExecProtocol is the transport of the exec calls
+	ExecProtocol string
+	// ExecChannel is how the commands are executed in the pods
+	ExecChannel string
+	// AgentPort is the port of the agent in the chaosblade tool pods
+	AgentPort int
+	// ExecRetryAttempts, ExecRetryBackoff and ExecRetryMaxBackoff are the default retry policy of the
+	// commands failed by the transient errors
+	ExecRetryAttempts   int
+	ExecRetryBackoff    time.Duration
+	ExecRetryMaxBackoff time.Duration
+	// ExecGlobalQPS and ExecGlobalBurst limit the exec calls of all experiments in the operator
+	ExecGlobalQPS   float32
+	ExecGlobalBurst int
+)
+
+const (
+	// PodsExecChannel executes the commands by the pods/exec of the apiserver and the kubelet
+	PodsExecChannel = "pods-exec"
+	// AgentChannel executes the commands by the agent in the chaosblade tool pod on the node
+	AgentChannel = "agent"
+)
+
+const (
+	// WebSocketProtocol streams over websocket and falls back to spdy if the upgrade is not supported
+	WebSocketProtocol = "websocket"
+	SPDYProtocol      = "spdy"
+)
+
+var f *pflag.FlagSet
+
+func init() {
+	f = pflag.NewFlagSet("channel", pflag.ExitOnError)
+	f.DurationVar(&ExecTimeout, "exec-timeout", 2*time.Minute, "The default timeout of the commands executed in the pods, no timeout if 0")
+	f.DurationVar(&ExecDeployTimeout, "exec-deploy-timeout", 10*time.Minute, "The timeout of copying or downloading the chaosblade tool into the pods, the exec-timeout is used if 0")
+	f.StringVar(&ExecProtocol, "exec-protocol", SPDYProtocol, "The transport of the commands executed in the pods, spdy or websocket. The websocket is opt-in and falls back to spdy if not supported by the cluster or the proxies")
+	f.StringVar(&ExecChannel, "exec-channel", PodsExecChannel, "The channel of the commands executed in the pods, pods-exec or agent. The agent requires the chaosblade tool daemonset running the chaos_agent")
+	f.IntVar(&AgentPort, "agent-port", agent.DefaultPort, "The port of the agent in the chaosblade tool pods")
+	f.IntVar(&ExecRetryAttempts, "exec-retry-attempts", 3, "The max attempts of the destroy commands executed in the pods failed before they start, such as the kubelet restart, the dial failure or the container not started yet. No retry if 1. The create commands are retried only if the experiment sets the exec-retry-attempts flag")
+	f.DurationVar(&ExecRetryBackoff, "exec-retry-backoff", time.Second, "The wait before the first retry of the commands executed in the pods, doubled after each retry")
+	f.DurationVar(&ExecRetryMaxBackoff, "exec-retry-max-backoff", 10*time.Second, "The max wait between the retries of the commands executed in the pods")
+	f.Float32Var(&ExecGlobalQPS, "exec-global-qps", 0, "The max exec calls per second of all experiments in the operator, including the deployments of the chaosblade tool and the status checks. No limit if 0, the default")
+	f.IntVar(&ExecGlobalBurst, "exec-global-burst", 100, "The max burst of the exec calls of all experiments in the operator")
+	f.IntVar(&ExecMaxOutputBytes, "exec-max-output-bytes", 1<<20, "The max bytes of the stdout and stderr kept for the commands executed in the pods, the rest is discarded. Unlimited if 0")
+}
+
+func FlagSet() *pflag.FlagSet {
+	return f
+}
+
+// ExecResult is the output and the exit code of the command executed in the pod
+type ExecResult struct {
+	Stdout   []byte
+	Stderr   []byte
+	ExitCode int
+	// Truncated is true if the stdout or stderr exceeds the max output bytes
+	Truncated bool
+}
+
+// ExecContext executes the command in the pod until it exits or the context is done. The timeout of the
+// options or ExecTimeout is applied if set, after the wait of the rate limiter of the context. The stdout
+// and stderr are kept up to the max output bytes and copied to the Out and ErrOut of the options if set.
+// A non-zero exit code is returned in the result, the error is only returned if the command cannot be
+// executed.
+func (c *Client) ExecContext(ctx context.Context, options *ExecOptions) (*ExecResult, error) {
+	// the wait of the rate limiter is not counted in the timeout
+	if err := waitRateLimiter(ctx); err != nil {
+		return &ExecResult{}, fmt.Errorf("wait the exec rate limit of pods/%s failed, %v", options.PodName, err)
+	}
+	timeout := options.Timeout
+	if timeout == 0 {
+		timeout = ExecTimeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	maxOutputBytes := options.MaxOutputBytes
+	if maxOutputBytes == 0 {
+		maxOutputBytes = ExecMaxOutputBytes
+	}
+	stdout := &limitedBuffer{limit: maxOutputBytes}
+	stderr := &limitedBuffer{limit: maxOutputBytes}
+	streamOptions := remotecommand.StreamOptions{
+		Stdout: stdout,
+		Stderr: stderr,
+		Tty:    options.TTY,
+	}
+	if options.Stdin {
+		streamOptions.Stdin = options.In
+	}
+	if options.Out != nil {
+		streamOptions.Stdout = io.MultiWriter(stdout, options.Out)
+	}
+	if options.ErrOut != nil {
+		streamOptions.Stderr = io.MultiWriter(stderr, options.ErrOut)
+	}
+
+	var err error
+	switch ExecChannel {
+	case AgentChannel:
+		err = c.agentExecute(ctx, options, streamOptions)
+	case PodsExecChannel:
+		request := c.CoreV1().RESTClient().Post().
+			Resource("pods").
+			Name(options.PodName).
+			Namespace(options.PodNamespace).
+			SubResource("exec").
+			VersionedParams(
+				&corev1.PodExecOptions{
+					Container: options.ContainerName,
+					Command:   options.Command,
+					Stdin:     options.Stdin,
+					Stdout:    true,
+					Stderr:    true,
+					TTY:       options.TTY,
+				}, scheme.ParameterCodec)
+		err = execute(ctx, "POST", request.URL(), c.Config, streamOptions)
+	default:
+		err = fmt.Errorf("unsupported exec channel %s, must be %s or %s", ExecChannel, PodsExecChannel, AgentChannel)
+	}
+	if errors.Is(err, errStreamAbandoned) {
+		// the abandoned stream may still write the buffers
+		return &ExecResult{}, fmt.Errorf("exec command in pods/%s timeout or canceled, %v", options.PodName, ctx.Err())
+	}
+	result := &ExecResult{
+		Stdout:    stdout.Bytes(),
+		Stderr:    stderr.Bytes(),
+		Truncated: stdout.truncated || stderr.truncated,
+	}
+	if exitErr, ok := err.(utilexec.ExitError); ok && exitErr.Exited() {
+		result.ExitCode = exitErr.ExitStatus()
+		return result, nil
+	}
+	if err != nil && ctx.Err() != nil {
+		return result, fmt.Errorf("exec command in pods/%s timeout or canceled, %v", options.PodName, ctx.Err())
+	}
+	return result, err
+}
+
+// errStreamAbandoned is returned if the context is done before the stream returns
+var errStreamAbandoned = errors.New("exec stream abandoned")
+
+// "172.21.1.11:8080/api/v1/namespaces/default/pods/my-nginx-3855515330-l1uqk/exec
+// ?container=my-nginx&stdin=1&stdout=1&stderr=1&tty=1&command=%2Fbin%2Fbash"
+// The spdy executor does not abort the upgrade by the context, so the stream is abandoned if the
+// context is done before it returns.
+func execute(ctx context.Context, method string, url *url.URL, config *rest.Config, options remotecommand.StreamOptions) error {
+	exec, err := newExecutor(method, url, config)
+	if err != nil {
+		return err
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- exec.StreamWithContext(ctx, options)
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return errStreamAbandoned
+	}
+}
+
+// newExecutor returns the executor of the ExecProtocol
+func newExecutor(method string, url *url.URL, config *rest.Config) (remotecommand.Executor, error) {
+	spdyExec, err := remotecommand.NewSPDYExecutor(config, method, url)
+	if err != nil {
+		return nil, err
+	}
+	switch ExecProtocol {
+	case SPDYProtocol:
+		return spdyExec, nil
+	case WebSocketProtocol:
+		// the websocket upgrade request must be a GET request
+		websocketExec, err := remotecommand.NewWebSocketExecutor(config, "GET", url.String())
+		if err != nil {
+			return nil, err
+		}
+		return remotecommand.NewFallbackExecutor(websocketExec, spdyExec, func(err error) bool {
+			fallback := httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+			if fallback {
+				logrus.WithError(err).WithField("url", url.String()).Debugln("websocket exec is not supported, fall back to spdy")
+			}
+			return fallback
+		})
+	default:
+		return nil, fmt.Errorf("unsupported exec protocol %s, must be %s or %s", ExecProtocol, WebSocketProtocol, SPDYProtocol)
+	}
+}
+
+// limitedBuffer keeps the first limit bytes and discards the rest, the writes never fail
+// so that the stream is not broken by a large output
+type limitedBuffer struct {
+	bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.limit <= 0 {
+		return b.Buffer.Write(p)
+	}
+	if remaining := b.limit - b.Len(); remaining < len(p) {
+		b.truncated = true
+		if remaining > 0 {
+			b.Buffer.Write(p[:remaining])
+		}
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+// Err returns the error of the command exited with a non-zero code, the stderr is used as the message if not empty
+func (r *ExecResult) Err() error {
+	if r.ExitCode == 0 {
+		return nil
+	}
+	if stderr := strings.TrimSpace(string(r.Stderr)); stderr != "" {
+		return fmt.Errorf("command terminated with exit code %d, %s", r.ExitCode, stderr)
+	}
+	return fmt.Errorf("command terminated with exit code %d", r.ExitCode)
+}
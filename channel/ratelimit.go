@@ -0,0 +1,66 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+type rateLimiterKey struct{}
+
+// globalRateLimiter is shared by the exec calls of all experiments, it's created on the first call after
+// the flags are parsed. Nil if the exec-global-qps is not positive.
+var globalRateLimiter = sync.OnceValue(func() flowcontrol.RateLimiter {
+	return newRateLimiter(ExecGlobalQPS, ExecGlobalBurst)
+})
+
+func newRateLimiter(qps float32, burst int) flowcontrol.RateLimiter {
+	if qps <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return flowcontrol.NewTokenBucketRateLimiter(qps, burst)
+}
+
+// WithRateLimiter returns the context limiting the exec calls by the token bucket of the qps and the burst,
+// the exec calls of all contexts derived from it share the bucket. No limit if the qps is not positive.
+func WithRateLimiter(ctx context.Context, qps float32, burst int) context.Context {
+	limiter := newRateLimiter(qps, burst)
+	if limiter == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, rateLimiterKey{}, limiter)
+}
+
+// waitRateLimiter blocks until the exec call is allowed by the rate limiter of the context,
+// then by the operator-wide rate limiter
+func waitRateLimiter(ctx context.Context) error {
+	if limiter, ok := ctx.Value(rateLimiterKey{}).(flowcontrol.RateLimiter); ok {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	if limiter := globalRateLimiter(); limiter != nil {
+		return limiter.Wait(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package channel
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// RetryPolicy is the exponential backoff of the exec calls failed by the transient errors
+type RetryPolicy struct {
+	// Attempts is the max number of the calls, including the first one
+	Attempts int
+	// Backoff is the wait before the first retry, doubled after each retry
+	Backoff time.Duration
+	// MaxBackoff is the upper bound of the wait between the retries
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy of the flags
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		Attempts:   ExecRetryAttempts,
+		Backoff:    ExecRetryBackoff,
+		MaxBackoff: ExecRetryMaxBackoff,
+	}
+}
+
+// containerNotReadyMessages are the messages of the container not created or not started yet, usually
+// right after the pod starts, the command is not executed
+var containerNotReadyMessages = []string{
+	"container not found",
+	"no such container",
+	"is not started",
+	"container not running",
+}
+
+// dialMessages are the messages of the errors caused by the kubelet restart or the network before the
+// stream is established, the command is not executed. The errors after the stream is established, such as
+// the connection reset or the unexpected EOF, are not retryable since the command may be executed.
+var dialMessages = []string{
+	"error dialing backend",
+	"connection refused",
+	"tls handshake timeout",
+}
+
+// IsRetryableError returns true if the exec error occurs before the command starts and the call can be
+// retried, such as the dial failure, the 5xx response of the upgrade and the container not running. The
+// timeout, the cancellation and the errors of the interrupted stream are fatal, the command may still run
+// in the container.
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var agentErr *agentResponseError
+	if errors.As(err, &agentErr) {
+		return agentErr.code >= 500 || agentErr.code == 429
+	}
+	if apierrors.IsServerTimeout(err) || apierrors.IsTooManyRequests(err) || apierrors.IsServiceUnavailable(err) ||
+		apierrors.IsInternalError(err) || apierrors.IsTimeout(err) {
+		return true
+	}
+	if utilnet.IsConnectionRefused(err) {
+		return true
+	}
+	message := strings.ToLower(err.Error())
+	return containsAny(message, containerNotReadyMessages) || containsAny(message, dialMessages)
+}
+
+// isRetryableResult returns true if the command is not executed because the container is not found or not
+// running, the runtime reports it in the stderr with a non-zero exit code and without any stdout
+func isRetryableResult(result *ExecResult) bool {
+	if result == nil || result.ExitCode == 0 || len(strings.TrimSpace(string(result.Stdout))) > 0 {
+		return false
+	}
+	return containsAny(strings.ToLower(string(result.Stderr)), containerNotReadyMessages)
+}
+
+func containsAny(s string, substrs []string) bool {
+	for _, substr := range substrs {
+		if strings.Contains(s, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExecContextWithRetry calls ExecContext until the command is executed, a fatal error occurs or the attempts
+// of the policy are used up, and returns the last result and the number of the calls. The calls with stdin
+// are not retried since the stdin cannot be replayed, and the Out and ErrOut of the options receive the
+// output of all calls.
+func (c *Client) ExecContextWithRetry(ctx context.Context, options *ExecOptions, policy RetryPolicy) (*ExecResult, int, error) {
+	attempts := policy.Attempts
+	if attempts < 1 || options.Stdin {
+		attempts = 1
+	}
+	backoff := wait.Backoff{
+		Duration: policy.Backoff,
+		Factor:   2,
+		Jitter:   0.1,
+		Steps:    attempts,
+		Cap:      policy.MaxBackoff,
+	}
+	for attempt := 1; ; attempt++ {
+		result, err := c.ExecContext(ctx, options)
+		if attempt >= attempts || !(IsRetryableError(err) || err == nil && isRetryableResult(result)) {
+			return result, attempt, err
+		}
+		delay := backoff.Step()
+		logFields := logrus.WithFields(logrus.Fields{
+			"podName":      options.PodName,
+			"podNamespace": options.PodNamespace,
+			"container":    options.ContainerName,
+			"attempt":      attempt,
+			"backoff":      delay,
+		})
+		if err != nil {
+			logFields.WithError(err).Warnln("Exec command in pod failed, retry it")
+		} else {
+			logFields.WithField("err", strings.TrimSpace(string(result.Stderr))).Warnln("Exec command in pod failed, retry it")
+		}
+		select {
+		case <-ctx.Done():
+			return result, attempt, err
+		case <-time.After(delay):
+		}
+	}
+}
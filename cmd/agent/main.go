@@ -0,0 +1,62 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
+
+	"github.com/chaosblade-io/chaosblade-operator/pkg/agent"
+)
+
+var (
+	address  string
+	authDir  string
+	nsexec   string
+	logLevel string
+	insecure bool
+)
+
+func main() {
+	flag.StringVar(&address, "address", fmt.Sprintf(":%d", agent.DefaultPort), "The address to bind")
+	flag.StringVar(&authDir, "auth-dir", "", "The directory contains the token and the serving certificate, serve https and require the bearer token")
+	flag.StringVar(&nsexec, "nsexec", "/opt/chaosblade/bin/nsexec", "The nsexec to run the commands in the namespaces of the containers")
+	flag.StringVar(&logLevel, "log-level", "info", "Log level, such as panic|fatal|error|warn|info|debug|trace")
+	flag.BoolVar(&insecure, "insecure", false, "Serve http without the authentication if the auth-dir is not set, only for testing")
+	flag.Parse()
+
+	level, err := logrus.ParseLevel(logLevel)
+	if err != nil {
+		level = logrus.InfoLevel
+	}
+	logrus.SetLevel(level)
+
+	if authDir == "" && !insecure {
+		logrus.Fatalln("The auth-dir is required, the agent runs commands on the node")
+	}
+	logFields := logrus.WithFields(logrus.Fields{
+		"address": address,
+		"authDir": authDir,
+	})
+	logFields.Infoln("Start chaosblade agent server.")
+	if err := agent.NewServer(address, authDir, nsexec).Start(signals.SetupSignalHandler()); err != nil {
+		logFields.WithError(err).Fatalln("Start chaosblade agent server failed")
+	}
+}
@@ -23,10 +23,11 @@ import (
 	"math/rand"
 	"os"
 	"os/exec"
+	"path"
+	"strings"
 	"time"
 
 	"github.com/chaosblade-io/chaosblade-spec-go/util"
-	"github.com/ethercflow/hookfs/hookfs"
 	"github.com/sirupsen/logrus"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 
@@ -38,24 +39,70 @@ var (
 	pidFile    string
 	original   string
 	mountpoint string
+	logLevel   string
+	authDir    string
+	targetPid  int
+	authEnv    bool
+	mounts     mountsFlag
 )
 
 func main() {
+	if isFusermount() {
+		if err := fusermount(os.Args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 	flag.StringVar(&address, "address", ":65534", "The address to bind")
 	flag.StringVar(&original, "original", "", "Mapping of the original disk, not affected by the drill")
 	flag.StringVar(&mountpoint, "mountpoint", "", "The disk of the drill. The affected directories are controlled by the path flag.")
+	flag.Var(&mounts, "mount", "The original:mountpoint of the disk of the drill, repeat it for multiple disks. The original and mountpoint flags are ignored if set")
+	flag.StringVar(&logLevel, "log-level", "info", "Log level, such as panic|fatal|error|warn|info|debug|trace")
+	flag.IntVar(&targetPid, "target-pid", 0, "Mount over the mountpoint in the mount namespace of the target process, the original flag is ignored. Used by the ephemeral container attached to a running pod")
+	flag.BoolVar(&authEnv, "auth-from-env", false, "Read the token and the serving certificate from the envs instead of the auth-dir")
+	flag.StringVar(&authDir, "auth-dir", "", "The directory contains the token and the serving certificate, serve https and require the bearer token if set")
 	rand.Seed(time.Now().UnixNano())
 	flag.Parse()
 
+	level, err := logrus.ParseLevel(logLevel)
+	if err != nil {
+		level = logrus.InfoLevel
+	}
+	logrus.SetLevel(level)
+
+	if targetPid > 0 {
+		if original, err = setupTarget(targetPid, mountpoint); err != nil {
+			logrus.WithError(err).Fatalln("Setup target failed")
+		}
+	}
+	if authEnv {
+		if authDir, err = writeAuthEnv(); err != nil {
+			logrus.WithError(err).Fatalln("Write auth envs failed")
+		}
+	}
 	logFields := logrus.WithFields(logrus.Fields{
 		"address":    address,
 		"original":   original,
 		"mountpoint": mountpoint,
+		"mounts":     mounts.String(),
+		"targetPid":  targetPid,
 	})
-	stopCtx := signals.SetupSignalHandler()
+	stopCtx, shutdown := context.WithCancel(signals.SetupSignalHandler())
+	defer shutdown()
 	chaosbladeHookServer := chaosbladehook.NewChaosbladeHookServer(address)
-	logFields.Infoln("Start chaosblade hook server.")
-	go chaosbladeHookServer.Start(stopCtx)
+	if authDir != "" {
+		chaosbladeHookServer = chaosbladehook.NewSecureChaosbladeHookServer(address, authDir)
+	}
+	if targetPid > 0 {
+		chaosbladeHookServer.SetShutdownHandler(shutdown)
+	}
+	logFields.WithField("authDir", authDir).Infoln("Start chaosblade hook server.")
+	go func() {
+		if err := chaosbladeHookServer.Start(stopCtx); err != nil {
+			logFields.WithError(err).Fatalln("Start chaosblade hook server failed")
+		}
+	}()
 
 	logFields.Infoln("Start fuse server.")
 	if err := startFuseServer(stopCtx); err != nil {
@@ -65,40 +112,46 @@ func main() {
 
 // startFuseServer starts hookfs server
 func startFuseServer(stop context.Context) error {
-	if !util.IsExist(original) {
-		if err := os.MkdirAll(original, os.FileMode(755)); err != nil {
-			return fmt.Errorf("create original directory error, %v", err)
-		}
+	if targetPid > 0 {
+		return serveFuse(stop, []fuseMount{{original: original, mountpoint: mountpoint}})
+	}
+	if len(mounts) == 0 {
+		mounts = append(mounts, fuseMount{original: original, mountpoint: mountpoint})
 	}
-	if !util.IsExist(mountpoint) {
-		if err := os.MkdirAll(mountpoint, os.FileMode(755)); err != nil {
-			return fmt.Errorf("create mountpoint directory error, %v", err)
+	for _, m := range mounts {
+		if !util.IsExist(m.original) {
+			if err := os.MkdirAll(m.original, os.FileMode(755)); err != nil {
+				return fmt.Errorf("create original directory error, %v", err)
+			}
+		}
+		if !util.IsExist(m.mountpoint) {
+			if err := os.MkdirAll(m.mountpoint, os.FileMode(755)); err != nil {
+				return fmt.Errorf("create mountpoint directory error, %v", err)
+			}
 		}
 	}
-	fs, err := hookfs.NewHookFs(original, mountpoint, &chaosbladehook.ChaosbladeHook{MountPoint: mountpoint})
-	if err != nil {
-		return fmt.Errorf("create hookfs error, %v", err)
+	return serveFuse(stop, mounts)
+}
+
+// serveFuse serves the fuse file systems until stopped, and unmounts them
+func serveFuse(stop context.Context, fuseMounts []fuseMount) error {
+	errCh := make(chan error, len(fuseMounts))
+	for _, m := range fuseMounts {
+		fs := chaosbladehook.NewChaosbladeFs(m.original, m.mountpoint)
+		go func() {
+			errCh <- fs.Serve()
+		}()
 	}
-	errCh := make(chan error)
-	go func() {
-		errCh <- fs.Serve()
-	}()
 	for {
 		select {
 		case <-stop.Done():
-			logFields := logrus.WithFields(logrus.Fields{
-				"address":    address,
-				"original":   original,
-				"mountpoint": mountpoint,
-			})
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-			cmd := exec.CommandContext(ctx, "fusermount", "-zu", mountpoint)
-			logFields.Infof("Start unmount fuse volume, cmd: %v", cmd)
-			if err := cmd.Run(); err != nil {
-				logFields.WithError(err).Errorln("Failed to execute fusermount")
+			var unmountErr error
+			for _, m := range fuseMounts {
+				if err := unmount(m); err != nil {
+					unmountErr = err
+				}
 			}
-			return err
+			return unmountErr
 		case err := <-errCh:
 			if err != nil {
 				return err
@@ -106,3 +159,68 @@ func startFuseServer(stop context.Context) error {
 		}
 	}
 }
+
+func unmount(m fuseMount) error {
+	logFields := logrus.WithFields(logrus.Fields{
+		"address":    address,
+		"original":   m.original,
+		"mountpoint": m.mountpoint,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "fusermount", "-zu", m.mountpoint)
+	logFields.Infof("Start unmount fuse volume, cmd: %v", cmd)
+	err := cmd.Run()
+	if err != nil {
+		logFields.WithError(err).Errorln("Failed to execute fusermount")
+	}
+	return err
+}
+
+type fuseMount struct {
+	original   string
+	mountpoint string
+}
+
+// mountsFlag is the repeatable flag of the mounts in original:mountpoint format
+type mountsFlag []fuseMount
+
+func (f *mountsFlag) String() string {
+	values := make([]string, 0)
+	for _, m := range *f {
+		values = append(values, m.original+":"+m.mountpoint)
+	}
+	return strings.Join(values, ",")
+}
+
+func (f *mountsFlag) Set(value string) error {
+	ss := strings.SplitN(value, ":", 2)
+	if len(ss) != 2 || ss[0] == "" || ss[1] == "" {
+		return fmt.Errorf("illegal mount %s, the format is original:mountpoint", value)
+	}
+	*f = append(*f, fuseMount{original: ss[0], mountpoint: ss[1]})
+	return nil
+}
+
+// writeAuthEnv writes the token and the serving certificate in the envs to a temporary auth directory
+func writeAuthEnv() (string, error) {
+	dir, err := os.MkdirTemp("", "chaosblade-fuse-auth")
+	if err != nil {
+		return "", err
+	}
+	files := map[string]string{
+		chaosbladehook.AuthTokenKey: chaosbladehook.AuthTokenEnv,
+		chaosbladehook.AuthCertKey:  chaosbladehook.AuthCertEnv,
+		chaosbladehook.AuthKeyKey:   chaosbladehook.AuthKeyEnv,
+	}
+	for file, env := range files {
+		value := os.Getenv(env)
+		if value == "" {
+			return "", fmt.Errorf("env %s is empty", env)
+		}
+		if err := os.WriteFile(path.Join(dir, file), []byte(value), 0600); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/chaosblade-io/chaosblade-operator/pkg/hookfs/nsenter"
+)
+
+const fusermountScript = `#!/bin/sh
+export %s=%d
+exec %s "$@"
+`
+
+// setupTarget prepares mounting over the mountpoint in the mount namespace of the target process.
+// It returns the original directory which refers to the target directory under the fuse mount, and
+// installs a fusermount which enters the mount namespace of the target before mounting.
+func setupTarget(targetPid int, mountpoint string) (string, error) {
+	targetPath := path.Join("/proc", strconv.Itoa(targetPid), "root", mountpoint)
+	// the opened directory keeps referring to the original directory after being mounted over
+	fd, err := syscall.Open(targetPath, syscall.O_RDONLY|syscall.O_DIRECTORY, 0)
+	if err != nil {
+		return "", fmt.Errorf("open target directory %s error, %v", targetPath, err)
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("get executable error, %v", err)
+	}
+	binDir, err := os.MkdirTemp("", "chaosblade-fuse")
+	if err != nil {
+		return "", fmt.Errorf("create fusermount directory error, %v", err)
+	}
+	script := fmt.Sprintf(fusermountScript, nsenter.TargetPidEnv, targetPid, executable)
+	if err := os.WriteFile(path.Join(binDir, "fusermount"), []byte(script), 0755); err != nil {
+		return "", fmt.Errorf("create fusermount error, %v", err)
+	}
+	if err := os.Setenv("PATH", binDir+":"+os.Getenv("PATH")); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("/proc/self/fd/%d", fd), nil
+}
+
+// isFusermount returns true if the process is started as the fusermount installed by setupTarget
+func isFusermount() bool {
+	return os.Getenv(nsenter.TargetPidEnv) != ""
+}
+
+// fusermount mounts or unmounts the fuse file system like the fusermount utility does, the
+// process has entered the mount namespace of the target
+func fusermount(args []string) error {
+	var unmount, lazy bool
+	var options, mountpoint string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-o" && i+1 < len(args):
+			options = args[i+1]
+			i++
+		case arg == "--":
+		case strings.HasPrefix(arg, "-"):
+			unmount = unmount || strings.Contains(arg, "u")
+			lazy = lazy || strings.Contains(arg, "z")
+		default:
+			mountpoint = arg
+		}
+	}
+	if mountpoint == "" {
+		return fmt.Errorf("mountpoint is required")
+	}
+	if unmount {
+		flags := 0
+		if lazy {
+			flags = syscall.MNT_DETACH
+		}
+		return syscall.Unmount(mountpoint, flags)
+	}
+	return mountFuse(mountpoint, options)
+}
+
+func mountFuse(mountpoint, options string) error {
+	fd := nsenter.FuseDevFd()
+	if fd < 0 {
+		return fmt.Errorf("the fuse device is not opened")
+	}
+	commFd, err := strconv.Atoi(os.Getenv("_FUSE_COMMFD"))
+	if err != nil {
+		return fmt.Errorf("illegal _FUSE_COMMFD, %v", err)
+	}
+	source, fsType := "chaosbladefs", "fuse"
+	data := []string{
+		fmt.Sprintf("fd=%d", fd),
+		fmt.Sprintf("rootmode=%o", syscall.S_IFDIR),
+		fmt.Sprintf("user_id=%d", os.Getuid()),
+		fmt.Sprintf("group_id=%d", os.Getgid()),
+	}
+	for _, option := range strings.Split(options, ",") {
+		switch {
+		case option == "":
+		case strings.HasPrefix(option, "fsname="):
+			source = strings.TrimPrefix(option, "fsname=")
+		case strings.HasPrefix(option, "subtype="):
+			fsType = "fuse." + strings.TrimPrefix(option, "subtype=")
+		default:
+			data = append(data, option)
+		}
+	}
+	if err := syscall.Mount(source, mountpoint, fsType, syscall.MS_NOSUID|syscall.MS_NODEV, strings.Join(data, ",")); err != nil {
+		return fmt.Errorf("mount fuse on %s error, %v", mountpoint, err)
+	}
+	return syscall.Sendmsg(commFd, []byte{0}, syscall.UnixRights(fd), nil, 0)
+}
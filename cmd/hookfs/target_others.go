@@ -0,0 +1,33 @@
+//go:build !linux
+
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import "fmt"
+
+func setupTarget(targetPid int, mountpoint string) (string, error) {
+	return "", fmt.Errorf("mounting in the target mount namespace is only supported on linux")
+}
+
+func isFusermount() bool {
+	return false
+}
+
+func fusermount(args []string) error {
+	return fmt.Errorf("fusermount is only supported on linux")
+}
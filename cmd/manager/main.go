@@ -34,6 +34,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtimeutil "k8s.io/apimachinery/pkg/util/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	apiruntime "k8s.io/apimachinery/pkg/runtime"
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -47,6 +48,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 
 	"github.com/chaosblade-io/chaosblade-operator/channel"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/agent"
 	"github.com/chaosblade-io/chaosblade-operator/pkg/apis"
 	"github.com/chaosblade-io/chaosblade-operator/pkg/controller"
 	operator "github.com/chaosblade-io/chaosblade-operator/pkg/runtime"
@@ -119,6 +121,18 @@ func addComponentsToManager(mgr manager.Manager) {
 	if err := controller.AddToManager(mgr); err != nil {
 		logrus.Fatalf("Add all controllers to manager error, %v", err)
 	}
+	if channel.ExecChannel == channel.AgentChannel {
+		if err := agent.EnsureAuthSecret(context.Background(), mgr.GetClient().(*channel.Client).Interface,
+			chaosblade.DaemonsetPodNamespace); err != nil {
+			logrus.Fatalf("Ensure agent auth secret error, %v", err)
+		}
+	}
+	// the sidecar template is used by the webhook and the ephemeral fuse container
+	if err := mgr.Add(&mutator.SidecarTemplateWatcher{
+		Clientset: mgr.GetClient().(*channel.Client).Interface,
+	}); err != nil {
+		logrus.Fatalf("Add sidecar template watcher to manager error, %v", err)
+	}
 	if webhookcfg.Enable {
 		logrus.Infof("Webhook enabled, add it to manager")
 		if err := addWebhook(mgr); err != nil {
@@ -138,14 +152,37 @@ func initLogger() {
 }
 
 func addWebhook(m manager.Manager) error {
+	if webhookcfg.CertAuto {
+		certManager := &webhookcfg.CertManager{
+			Clientset: m.GetClient().(*channel.Client).Interface,
+		}
+		// the certificates must exist before the webhook server starts
+		if err := certManager.Ensure(context.Background()); err != nil {
+			return err
+		}
+		if err := m.Add(certManager); err != nil {
+			return err
+		}
+	}
 	server := webhook.NewServer(webhook.Options{
-		Port: webhookcfg.Port,
+		Port:    webhookcfg.Port,
+		CertDir: webhookcfg.CertDir,
 	})
 	if err := m.Add(server); err != nil {
 		return err
 	}
-	logrus.Infof("registering %s to the webhook server", "mutating-pods")
-	server.Register("/mutating-pods", &webhook.Admission{Handler: &mutator.Mutator{}})
+	if webhookcfg.ManageConfiguration {
+		if err := m.Add(&webhookcfg.ConfigurationManager{
+			Clientset: m.GetClient().(*channel.Client).Interface,
+		}); err != nil {
+			return err
+		}
+	}
+	logrus.Infof("registering %s to the webhook server", webhookcfg.MutatingPodsPath)
+	server.Register(webhookcfg.MutatingPodsPath, &webhook.Admission{
+		Handler: mutator.NewMutator(m.GetClient(), m.GetClient().(*channel.Client).Interface,
+			admission.NewDecoder(m.GetScheme())),
+	})
 	return nil
 }
 
@@ -97,6 +97,11 @@ func (e *ExpController) Create(ctx context.Context, expSpec v1alpha1.ExperimentS
 	return e.Exec(ctx, expModel)
 }
 
+// SelectTargets returns the names of the pods matched by the experiment
+func (e *ExpController) SelectTargets(ctx context.Context, expSpec v1alpha1.ExperimentSpec) ([]string, *spec.Response) {
+	return e.SelectPodTargets(ctx, expSpec)
+}
+
 // Destroy
 func (e *ExpController) Destroy(ctx context.Context, expSpec v1alpha1.ExperimentSpec, oldExpStatus v1alpha1.ExperimentStatus) *spec.Response {
 	logrus.WithField("experiment", model.GetExperimentIdFromContext(ctx)).WithField("location", util.GetRunFuncName()).Infof("start to destroy")
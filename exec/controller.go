@@ -18,6 +18,8 @@ package exec
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/chaosblade-io/chaosblade-spec-go/spec"
@@ -29,6 +31,7 @@ import (
 	"github.com/chaosblade-io/chaosblade-operator/exec/node"
 	"github.com/chaosblade-io/chaosblade-operator/exec/pod"
 	"github.com/chaosblade-io/chaosblade-operator/pkg/apis/chaosblade/v1alpha1"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/runtime/chaosblade"
 )
 
 // ResourceDispatchedController contains all resource controllers exclude node resource
@@ -61,22 +64,50 @@ func (e *ResourceDispatchedController) Name() string {
 }
 
 func (e *ResourceDispatchedController) Create(bladeName string, expSpec v1alpha1.ExperimentSpec) v1alpha1.ExperimentStatus {
+	return e.create(model.SetExperimentIdToContext(context.Background(), bladeName), bladeName, expSpec)
+}
+
+// CreateConcurrently creates the experiments by the parallelism concurrently. All experiments are created
+// together if startBarrier is true, and the injections start after the targets of all experiments are
+// selected or the barrier timeout is reached.
+func (e *ResourceDispatchedController) CreateConcurrently(bladeName string, expSpecs []v1alpha1.ExperimentSpec,
+	parallelism int, startBarrier bool,
+) []v1alpha1.ExperimentStatus {
+	statuses := make([]v1alpha1.ExperimentStatus, len(expSpecs))
+	var barrier *model.StartBarrier
+	if startBarrier && len(expSpecs) > 1 {
+		barrier = model.NewStartBarrier(len(expSpecs), chaosblade.StartBarrierTimeout)
+		parallelism = len(expSpecs)
+	}
+	model.ParallelizeExecWithWorkers(parallelism, len(expSpecs), func(i int) {
+		ctx := model.SetExperimentIdToContext(context.Background(), bladeName)
+		if barrier != nil {
+			party := barrier.Party()
+			// the experiment failed before injecting must not hold the others
+			defer party.Leave()
+			ctx = model.SetBarrierPartyToContext(ctx, party)
+		}
+		statuses[i] = e.create(ctx, bladeName, expSpecs[i])
+	})
+	return statuses
+}
+
+func (e *ResourceDispatchedController) create(ctx context.Context, bladeName string, expSpec v1alpha1.ExperimentSpec) v1alpha1.ExperimentStatus {
 	logrus.WithField("experiment", bladeName).Infof("start to create experiment")
 	controller := e.Controllers[expSpec.Scope]
 	if controller == nil {
 		logrus.WithField("experiment", bladeName).WithField("scope", expSpec.Scope).Errorf("controller not found")
-		return v1alpha1.ExperimentStatus{
+		return setExperimentSpec(v1alpha1.ExperimentStatus{
 			State: "Error",
 			Error: "can not find the scope controller for creating",
-		}
+		}, expSpec)
 	}
-	ctx := model.SetExperimentIdToContext(context.Background(), bladeName)
-	response := controller.Create(ctx, expSpec)
-	experimentStatus := createExperimentStatusByResponse(response)
-	experimentStatus.Scope = expSpec.Scope
-	experimentStatus.Target = expSpec.Target
-	experimentStatus.Action = expSpec.Action
-	return experimentStatus
+	policy, resp := model.GetSuccessPolicy(model.ExtractExpModelFromExperimentSpec(expSpec).ActionFlags)
+	if !resp.Success {
+		return setExperimentSpec(v1alpha1.CreateFailExperimentStatus(resp.Err, []v1alpha1.ResourceStatus{}), expSpec)
+	}
+	response := controller.Create(model.SetExperimentNameToContext(ctx, expSpec.Name), expSpec)
+	return setExperimentSpec(policy.Apply(createExperimentStatusByResponse(response)), expSpec)
 }
 
 func (e *ResourceDispatchedController) Destroy(bladeName string, expSpec v1alpha1.ExperimentSpec, oldExpStatus v1alpha1.ExperimentStatus) v1alpha1.ExperimentStatus {
@@ -87,9 +118,21 @@ func (e *ResourceDispatchedController) Destroy(bladeName string, expSpec v1alpha
 			Error: "can not find the scope controller for destroying",
 		}
 	}
+	// the status of another experiment must not be destroyed by the spec
+	if oldExpStatus.Name != "" && expSpec.Name != "" && oldExpStatus.Name != expSpec.Name {
+		logrus.WithField("experiment", bladeName).Errorf("the status of the %s experiment does not match the %s experiment",
+			oldExpStatus.Name, expSpec.Name)
+		oldExpStatus.Success = false
+		oldExpStatus.State = v1alpha1.ErrorState
+		oldExpStatus.Error = fmt.Sprintf("the status of the %s experiment does not match the %s experiment",
+			oldExpStatus.Name, expSpec.Name)
+		return oldExpStatus
+	}
 	if oldExpStatus.ResStatuses == nil ||
 		len(oldExpStatus.ResStatuses) == 0 {
-		return model.CreateDestroyedStatus(oldExpStatus)
+		destroyedStatus := model.CreateDestroyedStatus(oldExpStatus)
+		destroyedStatus.Name = oldExpStatus.Name
+		return destroyedStatus
 	}
 	ctx := spec.SetDestroyFlag(context.Background(), bladeName)
 	ctx = model.SetExperimentIdToContext(ctx, bladeName)
@@ -99,11 +142,59 @@ func (e *ResourceDispatchedController) Destroy(bladeName string, expSpec v1alpha
 	return newExpStatus
 }
 
+// Update applies the new spec to the running experiment in place if the scope controller supports it, the
+// experiment is not changed if false is returned
+func (e *ResourceDispatchedController) Update(bladeName string, oldExpSpec, expSpec v1alpha1.ExperimentSpec,
+	oldExpStatus v1alpha1.ExperimentStatus,
+) (v1alpha1.ExperimentStatus, bool) {
+	controller := e.Controllers[expSpec.Scope]
+	updater, ok := controller.(model.ExperimentUpdater)
+	if !ok {
+		return oldExpStatus, false
+	}
+	policy, resp := model.GetSuccessPolicy(model.ExtractExpModelFromExperimentSpec(expSpec).ActionFlags)
+	if !resp.Success {
+		return oldExpStatus, false
+	}
+	ctx := model.SetExperimentIdToContext(context.Background(), bladeName)
+	response, ok := updater.Update(ctx, oldExpSpec, expSpec, oldExpStatus)
+	if !ok {
+		return oldExpStatus, false
+	}
+	return setExperimentSpec(policy.Apply(createExperimentStatusByResponse(response)), expSpec), true
+}
+
+// SelectTargets returns the names of the pods or the nodes selected by the experiment without injecting
+func (e *ResourceDispatchedController) SelectTargets(bladeName string, expSpec v1alpha1.ExperimentSpec) ([]string, error) {
+	selector, ok := e.Controllers[expSpec.Scope].(model.TargetSelector)
+	if !ok {
+		return nil, fmt.Errorf("the %s scope does not support selecting the targets", expSpec.Scope)
+	}
+	ctx := model.SetExperimentIdToContext(context.Background(), bladeName)
+	names, resp := selector.SelectTargets(ctx, expSpec)
+	if !resp.Success {
+		return nil, errors.New(resp.Err)
+	}
+	return names, nil
+}
+
+// setExperimentSpec records the experiment spec applied to the targets in the status
+func setExperimentSpec(experimentStatus v1alpha1.ExperimentStatus, expSpec v1alpha1.ExperimentSpec) v1alpha1.ExperimentStatus {
+	experimentStatus.Name = expSpec.Name
+	experimentStatus.Scope = expSpec.Scope
+	experimentStatus.Target = expSpec.Target
+	experimentStatus.Action = expSpec.Action
+	experimentStatus.AppliedSpec = expSpec.DeepCopy()
+	return experimentStatus
+}
+
 // validateAndSetNecessaryFields to resolve status overwriting when the experiment is destroyed.
 func validateAndSetNecessaryFields(status v1alpha1.ExperimentStatus, oldExpStatus v1alpha1.ExperimentStatus) v1alpha1.ExperimentStatus {
+	status.Name = oldExpStatus.Name
 	status.Scope = oldExpStatus.Scope
 	status.Target = oldExpStatus.Target
 	status.Action = oldExpStatus.Action
+	status.AppliedSpec = oldExpStatus.AppliedSpec
 	if status.State == "Error" {
 		status.State = oldExpStatus.State
 	}
@@ -0,0 +1,114 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const StartBarrierKey = "StartBarrierKey"
+
+// StartBarrier holds the experiments of a chaosblade after their targets are selected until all of them are
+// ready to inject, so that the experiments start together
+type StartBarrier struct {
+	lock    sync.Mutex
+	parties int
+	ready   chan struct{}
+	timeout time.Duration
+}
+
+// NewStartBarrier returns the barrier of the parties, the parties are released after the timeout even if some
+// of them are not arrived
+func NewStartBarrier(parties int, timeout time.Duration) *StartBarrier {
+	b := &StartBarrier{parties: parties, ready: make(chan struct{}), timeout: timeout}
+	if parties <= 0 {
+		close(b.ready)
+	}
+	return b
+}
+
+// Party returns the handle of an experiment to arrive at the barrier
+func (b *StartBarrier) Party() *BarrierParty {
+	return &BarrierParty{barrier: b}
+}
+
+func (b *StartBarrier) arrive() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.parties--
+	if b.parties == 0 {
+		close(b.ready)
+	}
+}
+
+// BarrierParty arrives at the barrier only once
+type BarrierParty struct {
+	barrier *StartBarrier
+	once    sync.Once
+}
+
+// Leave arrives at the barrier without waiting, such as the experiment failed before injecting
+func (p *BarrierParty) Leave() {
+	p.once.Do(p.barrier.arrive)
+}
+
+// Wait arrives at the barrier and waits for the other parties
+func (p *BarrierParty) Wait(ctx context.Context) {
+	p.Leave()
+	timer := time.NewTimer(p.barrier.timeout)
+	defer timer.Stop()
+	select {
+	case <-p.barrier.ready:
+	case <-timer.C:
+		logrus.WithField("experiment", GetExperimentIdFromContext(ctx)).
+			Warnf("the other experiments are not ready to inject after %s, start without them", p.barrier.timeout)
+	case <-ctx.Done():
+	}
+}
+
+// SetBarrierPartyToContext
+func SetBarrierPartyToContext(ctx context.Context, party *BarrierParty) context.Context {
+	return context.WithValue(ctx, StartBarrierKey, party)
+}
+
+// startBarrierWaiter is implemented by the action executors which wait for the start barrier by themselves,
+// right before the commands fan out to the targets
+type startBarrierWaiter interface {
+	waitsStartBarrier()
+}
+
+// execAfterStartBarrier waits for the start barrier before the creation fans out to the targets, which are
+// resolved and deployed with the chaosblade tool, so that the injections of the experiments start together
+func execAfterStartBarrier(ctx context.Context, isDestroy bool, policy FanOutPolicy, workCount int, doWork DoWorkFunc) {
+	if !isDestroy {
+		WaitStartBarrier(ctx)
+	}
+	policy.Exec(ctx, workCount, !isDestroy, doWork)
+}
+
+// WaitStartBarrier waits for the other experiments of the chaosblade if the experiment starts with a barrier
+func WaitStartBarrier(ctx context.Context) {
+	party, ok := ctx.Value(StartBarrierKey).(*BarrierParty)
+	if !ok || party == nil {
+		return
+	}
+	party.Wait(ctx)
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStartBarrier(t *testing.T) {
+	barrier := NewStartBarrier(3, time.Minute)
+	var started sync.WaitGroup
+	released := make(chan struct{}, 2)
+	for i := 0; i < 2; i++ {
+		started.Add(1)
+		go func() {
+			party := barrier.Party()
+			started.Done()
+			WaitStartBarrier(SetBarrierPartyToContext(context.Background(), party))
+			released <- struct{}{}
+		}()
+	}
+	started.Wait()
+	select {
+	case <-released:
+		t.Fatalf("the party is released before the others arrive")
+	case <-time.After(100 * time.Millisecond):
+	}
+	// the failed experiment leaves the barrier without waiting
+	party := barrier.Party()
+	party.Leave()
+	party.Leave()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-released:
+		case <-time.After(time.Second):
+			t.Fatalf("the party is not released after all parties arrive")
+		}
+	}
+}
+
+func TestStartBarrierTimeout(t *testing.T) {
+	barrier := NewStartBarrier(2, 50*time.Millisecond)
+	done := make(chan struct{})
+	go func() {
+		barrier.Party().Wait(context.Background())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("the party is not released after the timeout")
+	}
+}
+
+func TestWaitStartBarrierWithoutParty(t *testing.T) {
+	WaitStartBarrier(context.Background())
+}
+
+func TestExecAfterStartBarrier(t *testing.T) {
+	barrier := NewStartBarrier(2, time.Minute)
+	ctx := SetBarrierPartyToContext(context.Background(), barrier.Party())
+	// the targets are resolved and the tool is deployed, the commands wait for the other experiment
+	var lock sync.Mutex
+	executed := 0
+	done := make(chan struct{})
+	go func() {
+		execAfterStartBarrier(ctx, false, FanOutPolicy{Concurrency: 2}, 2, func(i int) {
+			lock.Lock()
+			executed++
+			lock.Unlock()
+		})
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatalf("the commands fan out before the other experiment is ready")
+	case <-time.After(100 * time.Millisecond):
+	}
+	barrier.Party().Leave()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("the commands are not executed after all experiments are ready")
+	}
+	if executed != 2 {
+		t.Errorf("expected the commands executed in 2 targets, got %d", executed)
+	}
+
+	// the destroy never waits
+	ctx = SetBarrierPartyToContext(context.Background(), NewStartBarrier(2, time.Minute).Party())
+	destroyed := make(chan struct{})
+	go func() {
+		execAfterStartBarrier(ctx, true, FanOutPolicy{Concurrency: 1}, 1, func(i int) {})
+		close(destroyed)
+	}()
+	select {
+	case <-destroyed:
+	case <-time.After(time.Second):
+		t.Fatalf("the destroy waits for the start barrier")
+	}
+}
+
+func TestCommandExecutorsWaitStartBarrier(t *testing.T) {
+	// the barrier is waited before the fan out of the commands instead of before resolving the targets
+	for _, executor := range []interface{}{&ExecCommandInPodExecutor{}, &CommonExecutor{}} {
+		if _, ok := executor.(startBarrierWaiter); !ok {
+			t.Errorf("expected %T waits for the start barrier by itself", executor)
+		}
+	}
+}
@@ -27,6 +27,7 @@ import (
 const (
 	ContainerObjectMetaListKey = "ContainerObjectMetaListKey"
 	ExperimentIdKey            = "ExperimentIdKey"
+	ExperimentNameKey          = "ExperimentNameKey"
 )
 
 type ContainerObjectMeta struct {
@@ -56,6 +57,17 @@ func SetExperimentIdToContext(ctx context.Context, experimentId string) context.
 	return context.WithValue(ctx, ExperimentIdKey, experimentId)
 }
 
+// GetExperimentNameFromContext returns the name of the experiment in the chaosblade, empty if not set
+func GetExperimentNameFromContext(ctx context.Context) string {
+	experimentName, _ := ctx.Value(ExperimentNameKey).(string)
+	return experimentName
+}
+
+// SetExperimentNameToContext
+func SetExperimentNameToContext(ctx context.Context, experimentName string) context.Context {
+	return context.WithValue(ctx, ExperimentNameKey, experimentName)
+}
+
 // GetContainerObjectMetaListFromContext returns the matched container list
 func GetContainerObjectMetaListFromContext(ctx context.Context) (ContainerMatchedList, error) {
 	containerObjectMetaListValue := ctx.Value(ContainerObjectMetaListKey)
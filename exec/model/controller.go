@@ -20,8 +20,10 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -36,8 +38,14 @@ type ExpController interface {
 	Name() string
 	// Create
 	Create(bladeName string, expSpec v1alpha1.ExperimentSpec) v1alpha1.ExperimentStatus
+	// CreateConcurrently creates the experiments by the parallelism, the statuses are in the order of the specs
+	CreateConcurrently(bladeName string, expSpecs []v1alpha1.ExperimentSpec, parallelism int, startBarrier bool) []v1alpha1.ExperimentStatus
 	// Destroy
 	Destroy(bladeName string, expSpec v1alpha1.ExperimentSpec, oldExpStatus v1alpha1.ExperimentStatus) v1alpha1.ExperimentStatus
+	// SelectTargets returns the names of the pods or the nodes selected by the experiment without injecting
+	SelectTargets(bladeName string, expSpec v1alpha1.ExperimentSpec) ([]string, error)
+	// Update applies the new spec to the targets of the running experiment in place, false is returned if not supported
+	Update(bladeName string, oldExpSpec, expSpec v1alpha1.ExperimentSpec, oldExpStatus v1alpha1.ExperimentStatus) (v1alpha1.ExperimentStatus, bool)
 }
 
 type ExperimentController interface {
@@ -49,6 +57,39 @@ type ExperimentController interface {
 	Destroy(ctx context.Context, expSpec v1alpha1.ExperimentSpec, oldExpStatus v1alpha1.ExperimentStatus) *spec.Response
 }
 
+// TargetSelector is implemented by the experiment controllers to select the targets of the experiment without
+// injecting, the names of the pods or the nodes are returned
+type TargetSelector interface {
+	SelectTargets(ctx context.Context, expSpec v1alpha1.ExperimentSpec) ([]string, *spec.Response)
+}
+
+// SelectPodTargets returns the names of the pods matched by the experiment
+func (b *BaseExperimentController) SelectPodTargets(ctx context.Context, expSpec v1alpha1.ExperimentSpec) ([]string, *spec.Response) {
+	pods, resp := b.GetMatchedPodResources(ctx, *ExtractExpModelFromExperimentSpec(expSpec))
+	if !resp.Success {
+		return nil, resp
+	}
+	names := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	return names, spec.Success()
+}
+
+// ExperimentUpdater is implemented by the experiment controllers which can update the running experiments in place
+type ExperimentUpdater interface {
+	Update(ctx context.Context, oldExpSpec, expSpec v1alpha1.ExperimentSpec, oldExpStatus v1alpha1.ExperimentStatus) (*spec.Response, bool)
+}
+
+// ActionUpdater is implemented by the action executors which can apply the changed flags to the targets of the
+// running experiment without destroying it
+type ActionUpdater interface {
+	// Updatable returns true if the changed flags can be applied in place
+	Updatable(changedFlags []string) bool
+	// Update applies the flags of the experiment to the targets in the context
+	Update(ctx context.Context, expModel *spec.ExpModel) *spec.Response
+}
+
 type BaseExperimentController struct {
 	Client            *channel.Client
 	ResourceModelSpec ResourceExpModelSpec
@@ -78,11 +119,102 @@ func (b *BaseExperimentController) Exec(ctx context.Context, expModel *spec.ExpM
 			v1alpha1.CreateFailExperimentStatus(errMsg, []v1alpha1.ResourceStatus{}), handler)
 	}
 	expModel.ActionPrograms = actionSpec.Programs()
+	executor := actionSpec.Executor()
+	if _, isDestroy := spec.IsDestroy(ctx); !isDestroy {
+		if _, ok := executor.(startBarrierWaiter); !ok {
+			WaitStartBarrier(ctx)
+		}
+	}
 	// invoke action executor
-	response := actionSpec.Executor().Exec(experimentId, ctx, expModel)
+	response := executor.Exec(experimentId, ctx, expModel)
 	return response
 }
 
+// Update applies the new spec to the successful targets of the running experiment if the action executor is an
+// ActionUpdater. False is returned if the targets selection or the timeout is changed.
+func (b *BaseExperimentController) Update(ctx context.Context, oldExpSpec, expSpec v1alpha1.ExperimentSpec,
+	oldExpStatus v1alpha1.ExperimentStatus,
+) (*spec.Response, bool) {
+	if oldExpSpec.Scope != expSpec.Scope || oldExpSpec.Target != expSpec.Target || oldExpSpec.Action != expSpec.Action {
+		return nil, false
+	}
+	actionSpec := b.ResourceModelSpec.GetExpActionModelSpec(expSpec.Target, expSpec.Action)
+	if actionSpec == nil {
+		return nil, false
+	}
+	updater, ok := actionSpec.Executor().(ActionUpdater)
+	if !ok {
+		return nil, false
+	}
+	changedFlags := ChangedFlags(oldExpSpec, expSpec)
+	resourceFlagNames := GetResourceFlagNames()
+	for _, flag := range changedFlags {
+		if _, ok := resourceFlagNames[flag]; ok || flag == "timeout" || flag == RampChangedFlag {
+			return nil, false
+		}
+	}
+	if !updater.Updatable(changedFlags) {
+		return nil, false
+	}
+	containerObjectMetaList := ContainerMatchedList{}
+	for _, status := range oldExpStatus.ResStatuses {
+		if !status.Success {
+			continue
+		}
+		containerObjectMeta := ParseIdentifier(status.Identifier)
+		containerObjectMeta.Id = status.Id
+		containerObjectMetaList = append(containerObjectMetaList, containerObjectMeta)
+	}
+	if len(containerObjectMetaList) == 0 {
+		return nil, false
+	}
+	logrus.WithField("experiment", GetExperimentIdFromContext(ctx)).
+		Infof("update the experiment in place, changed flags: %v", changedFlags)
+	expModel := ExtractExpModelFromExperimentSpec(expSpec)
+	expModel.ActionPrograms = actionSpec.Programs()
+	ctx = SetContainerObjectMetaListToContext(ctx, containerObjectMetaList)
+	return updater.Update(ctx, expModel), true
+}
+
+// RampChangedFlag is returned by ChangedFlags if the ramp steps are changed, the experiment with the changed ramp
+// is not updated in place
+const RampChangedFlag = "ramp"
+
+// ChangedFlags returns the sorted names of the flags added, removed or changed in the new experiment spec,
+// including the RampChangedFlag if the ramp steps are changed
+func ChangedFlags(oldExpSpec, expSpec v1alpha1.ExperimentSpec) []string {
+	oldFlags := ExtractExpModelFromExperimentSpec(oldExpSpec).ActionFlags
+	flags := ExtractExpModelFromExperimentSpec(expSpec).ActionFlags
+	changed := make([]string, 0)
+	for name, value := range flags {
+		if oldValue, ok := oldFlags[name]; !ok || oldValue != value {
+			changed = append(changed, name)
+		}
+	}
+	for name := range oldFlags {
+		if _, ok := flags[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	if !isSameRamp(oldExpSpec.Ramp, expSpec.Ramp) {
+		changed = append(changed, RampChangedFlag)
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+func isSameRamp(oldRamp, ramp []v1alpha1.RampStep) bool {
+	if len(oldRamp) != len(ramp) {
+		return false
+	}
+	for idx := range ramp {
+		if oldRamp[idx] != ramp[idx] {
+			return false
+		}
+	}
+	return true
+}
+
 // ExtractExpModelFromExperimentSpec convert ExperimentSpec to ExpModel
 func ExtractExpModelFromExperimentSpec(experimentSpec v1alpha1.ExperimentSpec) *spec.ExpModel {
 	expModel := &spec.ExpModel{
@@ -157,3 +289,35 @@ func CreateDestroyedStatus(oldExpStatus v1alpha1.ExperimentStatus) v1alpha1.Expe
 	}
 	return v1alpha1.CreateDestroyedExperimentStatus(statuses)
 }
+
+// GetExecRetryPolicy returns the retry policy of the chaosblade commands, the flags of the experiment
+// override the default policy of the operator. The create commands are not retried unless the experiment
+// sets the attempts, since the command may be executed even if the exec fails
+func GetExecRetryPolicy(flags map[string]string, isDestroy bool) (channel.RetryPolicy, *spec.Response) {
+	policy := channel.DefaultRetryPolicy()
+	if !isDestroy {
+		policy.Attempts = 1
+	}
+	if attemptsValue := flags[ExecRetryAttemptsFlag.Name]; attemptsValue != "" {
+		attempts, err := strconv.Atoi(attemptsValue)
+		if err != nil {
+			return policy, spec.ResponseFailWithFlags(spec.ParameterIllegal, ExecRetryAttemptsFlag.Name, attemptsValue, err)
+		}
+		if attempts < 1 {
+			return policy, spec.ResponseFailWithFlags(spec.ParameterIllegal, ExecRetryAttemptsFlag.Name, attemptsValue,
+				"it must be a positive integer")
+		}
+		policy.Attempts = attempts
+	}
+	if backoffValue := flags[ExecRetryBackoffFlag.Name]; backoffValue != "" {
+		backoff, err := time.ParseDuration(backoffValue)
+		if err != nil {
+			return policy, spec.ResponseFailWithFlags(spec.ParameterIllegal, ExecRetryBackoffFlag.Name, backoffValue, err)
+		}
+		policy.Backoff = backoff
+		if policy.MaxBackoff < backoff {
+			policy.MaxBackoff = backoff
+		}
+	}
+	return policy, spec.Success()
+}
@@ -18,6 +18,8 @@ package model
 
 import (
 	"testing"
+
+	"github.com/chaosblade-io/chaosblade-operator/channel"
 )
 
 func TestGetResourceCount(t *testing.T) {
@@ -52,3 +54,31 @@ func TestGetResourceCount(t *testing.T) {
 		})
 	}
 }
+
+func TestGetExecRetryPolicy(t *testing.T) {
+	defaultAttempts := channel.ExecRetryAttempts
+	channel.ExecRetryAttempts = 3
+	defer func() { channel.ExecRetryAttempts = defaultAttempts }()
+	tests := []struct {
+		name      string
+		flags     map[string]string
+		isDestroy bool
+		want      int
+	}{
+		{name: "create", flags: map[string]string{}, isDestroy: false, want: 1},
+		{name: "create with attempts", flags: map[string]string{ExecRetryAttemptsFlag.Name: "2"}, isDestroy: false, want: 2},
+		{name: "destroy", flags: map[string]string{}, isDestroy: true, want: 3},
+		{name: "destroy with attempts", flags: map[string]string{ExecRetryAttemptsFlag.Name: "5"}, isDestroy: true, want: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, resp := GetExecRetryPolicy(tt.flags, tt.isDestroy)
+			if !resp.Success {
+				t.Fatalf("GetExecRetryPolicy() failed, %s", resp.Err)
+			}
+			if policy.Attempts != tt.want {
+				t.Errorf("GetExecRetryPolicy() attempts = %d, want %d", policy.Attempts, tt.want)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package model
 
 import (
 	"archive/tar"
+	"context"
 	"errors"
 	"io"
 	"io/ioutil"
@@ -123,7 +124,7 @@ func recursiveTar(srcBase, srcFile, destBase, destFile string, tw *tar.Writer) e
 	return nil
 }
 
-func (o *CopyOptions) execute(options *channel.ExecOptions) error {
+func (o *CopyOptions) execute(ctx context.Context, options *channel.ExecOptions) error {
 	if len(options.PodNamespace) == 0 {
 		options.PodNamespace = o.Namespace
 	}
@@ -131,14 +132,11 @@ func (o *CopyOptions) execute(options *channel.ExecOptions) error {
 	if len(o.Container) > 0 {
 		options.ContainerName = o.Container
 	}
-	if err := o.client.Exec(options); err != nil {
-		return err.(error)
-	}
-	return nil
+	return o.exec(ctx, options)
 }
 
 // DeployToPod copies src file or directory to specify container
-func (o *CopyOptions) DeployToPod(experimentId, src, dest string) error {
+func (o *CopyOptions) DeployToPod(ctx context.Context, experimentId, src, dest string) error {
 	if len(src) == 0 || len(dest) == 0 {
 		return errors.New("filepath can not be empty")
 	}
@@ -169,18 +167,14 @@ func (o *CopyOptions) DeployToPod(experimentId, src, dest string) error {
 				In: reader,
 			},
 			Stdin: true,
-			ErrDecoder: func(bytes []byte) interface{} {
-				return errors.New(string(bytes))
-			},
-			OutDecoder: func(bytes []byte) interface{} {
-				return nil
-			},
 		},
 		PodName:       o.PodName,
 		PodNamespace:  o.Namespace,
 		ContainerName: o.Container,
 		Command:       cmdArr,
-		IgnoreOutput:  true,
+		Timeout:       channel.ExecDeployTimeout,
 	}
-	return o.execute(options)
+	// unblock the tar writer if the exec returns before reading all
+	defer reader.Close()
+	return o.execute(ctx, options)
 }
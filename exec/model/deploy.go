@@ -17,13 +17,20 @@
 package model
 
 import (
+	"context"
 	"errors"
 
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
 	"github.com/chaosblade-io/chaosblade-operator/channel"
+	mutator "github.com/chaosblade-io/chaosblade-operator/pkg/webhook/pod"
 )
 
 type DeployMode interface {
-	DeployToPod(experimentId, src, dest string) error
+	// DeployToPod deploys the src to the dest of the container until it completes, the ExecDeployTimeout
+	// expires or the context is done
+	DeployToPod(ctx context.Context, experimentId, src, dest string) error
 }
 
 type DeployOptions struct {
@@ -33,50 +40,42 @@ type DeployOptions struct {
 	client    *channel.Client
 }
 
+// HasToolkit returns true if the toolkit is preinstalled at the chaosblade path by the webhook
+func (o *DeployOptions) HasToolkit(ctx context.Context, chaosbladePath string) bool {
+	pod := &v1.Pod{}
+	if err := o.client.Get(ctx, types.NamespacedName{Namespace: o.Namespace, Name: o.PodName}, pod); err != nil {
+		return false
+	}
+	return mutator.HasToolkit(pod, o.Container, chaosbladePath)
+}
+
 // CheckFileExists return nil if dest file exists
-func (o *DeployOptions) CheckFileExists(dest string) error {
-	options := &channel.ExecOptions{
-		StreamOptions: channel.StreamOptions{
-			ErrDecoder: func(bytes []byte) interface{} {
-				return errors.New(string(bytes))
-			},
-			OutDecoder: func(bytes []byte) interface{} {
-				return nil
-			},
-		},
+func (o *DeployOptions) CheckFileExists(ctx context.Context, dest string) error {
+	return o.exec(ctx, &channel.ExecOptions{
 		PodNamespace:  o.Namespace,
 		PodName:       o.PodName,
 		ContainerName: o.Container,
 		Command:       []string{"test", "-e", dest},
-		IgnoreOutput:  true,
-	}
-	if err := o.client.Exec(options); err != nil {
-		return err.(error)
-	}
-	return nil
+	})
 }
 
-func (o *DeployOptions) CreateDir(dir string) error {
+func (o *DeployOptions) CreateDir(ctx context.Context, dir string) error {
 	if len(dir) == 0 {
 		return errors.New("illegal directory name")
 	}
-	options := &channel.ExecOptions{
-		StreamOptions: channel.StreamOptions{
-			ErrDecoder: func(bytes []byte) interface{} {
-				return errors.New(string(bytes))
-			},
-			OutDecoder: func(bytes []byte) interface{} {
-				return nil
-			},
-		},
+	return o.exec(ctx, &channel.ExecOptions{
 		PodName:       o.PodName,
 		PodNamespace:  o.Namespace,
 		ContainerName: o.Container,
 		Command:       []string{"mkdir", "-p", dir},
-		IgnoreOutput:  true,
-	}
-	if err := o.client.Exec(options); err != nil {
-		return err.(error)
+	})
+}
+
+// exec executes the command in the container, returns the error if it cannot be executed or exits with non-zero code
+func (o *DeployOptions) exec(ctx context.Context, options *channel.ExecOptions) error {
+	result, err := o.client.ExecContext(ctx, options)
+	if err != nil {
+		return err
 	}
-	return nil
+	return result.Err()
 }
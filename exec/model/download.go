@@ -17,6 +17,7 @@
 package model
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"path"
@@ -49,7 +50,7 @@ type DownloadOptions struct {
 	url string
 }
 
-func (d *DownloadOptions) DeployToPod(experimentId, src, dest string) error {
+func (d *DownloadOptions) DeployToPod(ctx context.Context, experimentId, src, dest string) error {
 	if len(src) == 0 {
 		return errors.New("the chaosblade downloaded address is empty")
 	}
@@ -75,8 +76,9 @@ func (d *DownloadOptions) DeployToPod(experimentId, src, dest string) error {
 		ContainerName: d.Container,
 		Command:       command,
 		IgnoreOutput:  false,
+		Timeout:       channel.ExecDeployTimeout,
 	}
-	statusCode := d.client.Exec(options).(string)
+	statusCode := d.client.Exec(ctx, options).(string)
 	logrus.WithFields(
 		logrus.Fields{
 			"experimentId": experimentId,
@@ -93,12 +95,12 @@ func (d *DownloadOptions) DeployToPod(experimentId, src, dest string) error {
 		return fmt.Errorf("response code is %d", code)
 	}
 	if isTarFile {
-		return d.uncompress(experimentId, dest)
+		return d.uncompress(ctx, experimentId, dest)
 	}
 	return nil
 }
 
-func (d *DownloadOptions) uncompress(experimentId, file string) error {
+func (d *DownloadOptions) uncompress(ctx context.Context, experimentId, file string) error {
 	dir := path.Dir(file)
 	command := []string{"/bin/sh", "-c", fmt.Sprintf("tar -zxf %s -C %s && chmod -R 755 %s", file, dir, dir)}
 	options := &channel.ExecOptions{
@@ -115,8 +117,9 @@ func (d *DownloadOptions) uncompress(experimentId, file string) error {
 		ContainerName: d.Container,
 		Command:       command,
 		IgnoreOutput:  true,
+		Timeout:       channel.ExecDeployTimeout,
 	}
-	error := d.client.Exec(options)
+	error := d.client.Exec(ctx, options)
 	logrus.WithFields(
 		logrus.Fields{
 			"experimentId": experimentId,
@@ -17,7 +17,6 @@
 package model
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -55,7 +54,8 @@ func checkExperimentStatus(ctx context.Context, expModel *spec.ExpModel, statuse
 			}
 			time.Sleep(time.Duration(timeout) * time.Second)
 
-			ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+			// the check runs after the experiment returns, it keeps the rate limiter of the experiment only
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Second*30)
 			defer cancel()
 
 			ticker := time.NewTicker(time.Second)
@@ -81,32 +81,24 @@ func checkExperimentStatus(ctx context.Context, expModel *spec.ExpModel, statuse
 							podNamespace = identifier.ChaosBladeNamespace
 							containerName = identifier.ChaosBladeContainerName
 						}
-						response := client.Exec(&channel.ExecOptions{
-							StreamOptions: channel.StreamOptions{
-								ErrDecoder: func(bytes []byte) interface{} {
-									content := string(bytes)
-									util.Errorf(identifier.Id, util.GetRunFuncName(), spec.K8sExecFailed.Sprintf("pods/exec", content))
-									return spec.Decode(content, spec.ResponseFailWithFlags(spec.K8sExecFailed, "pods/exec", content))
-								},
-								OutDecoder: func(bytes []byte) interface{} {
-									content := string(bytes)
-									util.Errorf(identifier.Id, util.GetRunFuncName(), spec.K8sExecFailed.Sprintf("pods/exec", content))
-									return spec.Decode(content, spec.ResponseFailWithFlags(spec.K8sExecFailed, "pods/exec", content))
-								},
-							},
+						var bladeStatus *BladeStatus
+						result, err := client.ExecContext(ctx, &channel.ExecOptions{
 							PodName:       podName,
 							PodNamespace:  podNamespace,
 							ContainerName: containerName,
 							Command:       []string{getTargetChaosBladeBin(expModel), "status", status.Id},
-							IgnoreOutput:  false,
-						}).(*spec.Response)
-						if response.Success {
-							result := response.Result.(map[string]interface{})
-							if result["Status"] != v1alpha1.DestroyedState {
-								isDestroyed = false
-								break
-							}
-						} else {
+						})
+						response := ParseExecResult(result, err, func(output []byte) *spec.Response {
+							var resp *spec.Response
+							bladeStatus, resp = ParseStatusResponse(output)
+							return resp
+						})
+						if !response.Success {
+							util.Errorf(identifier.Id, util.GetRunFuncName(), response.Err)
+							isDestroyed = false
+							break
+						}
+						if bladeStatus.Status != v1alpha1.DestroyedState {
 							isDestroyed = false
 							break
 						}
@@ -151,8 +143,8 @@ func checkExperimentStatus(ctx context.Context, expModel *spec.ExpModel, statuse
 	}
 }
 
-func execCommands(isDestroy bool, rsStatus v1alpha1.ResourceStatus,
-	identifier ExperimentIdentifierInPod, client *channel.Client,
+func execCommands(ctx context.Context, isDestroy bool, rsStatus v1alpha1.ResourceStatus,
+	identifier ExperimentIdentifierInPod, client *channel.Client, retryPolicy channel.RetryPolicy,
 ) (bool, v1alpha1.ResourceStatus) {
 	success := false
 	// handle chaos experiments using daemonset mode
@@ -164,43 +156,36 @@ func execCommands(isDestroy bool, rsStatus v1alpha1.ResourceStatus,
 		podNamespace = identifier.ChaosBladeNamespace
 		containerName = identifier.ChaosBladeContainerName
 	}
-	response := client.Exec(&channel.ExecOptions{
-		StreamOptions: channel.StreamOptions{
-			IOStreams: channel.IOStreams{
-				Out:    bytes.NewBuffer([]byte{}),
-				ErrOut: bytes.NewBuffer([]byte{}),
-			},
-			ErrDecoder: func(bytes []byte) interface{} {
-				content := string(bytes)
-				util.Errorf(identifier.Id, util.GetRunFuncName(), spec.K8sExecFailed.Sprintf("pods/exec", content))
-				return spec.Decode(content, spec.ResponseFailWithFlags(spec.K8sExecFailed, "pods/exec", content))
-			},
-			OutDecoder: func(bytes []byte) interface{} {
-				content := string(bytes)
-				util.Infof(identifier.Id, util.GetRunFuncName(), fmt.Sprintf("exec output: %s", content))
-				// TODO ?? 不应该返回错我
-				return spec.Decode(content, spec.ResponseFailWithFlags(spec.K8sExecFailed, "pods/exec", content))
-			},
-		},
+	result, attempts, err := client.ExecContextWithRetry(ctx, &channel.ExecOptions{
 		PodName:       podName,
 		PodNamespace:  podNamespace,
 		ContainerName: containerName,
 		Command:       strings.Split(identifier.Command, " "),
-	}).(*spec.Response)
+	}, retryPolicy)
+	response := ParseExecResult(result, err, func(output []byte) *spec.Response {
+		if isDestroy {
+			return ParseDestroyResponse(output)
+		}
+		uid, resp := ParseCreateResponse(output)
+		if resp.Success {
+			rsStatus.Id = uid
+		}
+		return resp
+	})
 
 	if response.Success {
-		if !isDestroy {
-			rsStatus.Id = response.Result.(string)
-		}
+		util.Infof(identifier.Id, util.GetRunFuncName(), fmt.Sprintf("exec output: %s", response.Print()))
 		rsStatus = rsStatus.CreateSuccessResourceStatus()
 		success = true
 	} else {
+		util.Errorf(identifier.Id, util.GetRunFuncName(), spec.K8sExecFailed.Sprintf("pods/exec", response.Err))
 		rsStatus = rsStatus.CreateFailResourceStatus(response.Err, response.Code)
 	}
+	rsStatus.Attempts = int32(attempts)
 	return success, rsStatus
 }
 
-func generateDestroyCommands(experimentId string, expModel *spec.ExpModel,
+func generateDestroyCommands(ctx context.Context, experimentId string, expModel *spec.ExpModel,
 	containerObjectMetaList ContainerMatchedList, matchers string, client *channel.Client,
 ) ([]ExperimentIdentifierInPod, error) {
 	command := fmt.Sprintf("%s destroy %s %s %s", getTargetChaosBladeBin(expModel), expModel.Target, expModel.ActionName, matchers)
@@ -214,7 +199,7 @@ func generateDestroyCommands(experimentId string, expModel *spec.ExpModel,
 			ContainerObjectMeta: containerObjectMetaList[idx],
 			Command:             generatedCommand,
 		}
-		resp := deployChaosBlade(experimentId, expModel, obj, false, client)
+		resp := deployChaosBlade(ctx, experimentId, expModel, obj, false, client)
 		if !resp.Success {
 			identifierInPod.Error = resp.Err
 			identifierInPod.Code = resp.Code
@@ -224,7 +209,7 @@ func generateDestroyCommands(experimentId string, expModel *spec.ExpModel,
 	return identifiers, nil
 }
 
-func generateCreateCommands(experimentId string, expModel *spec.ExpModel, containerObjectMetaList ContainerMatchedList,
+func generateCreateCommands(ctx context.Context, experimentId string, expModel *spec.ExpModel, containerObjectMetaList ContainerMatchedList,
 	matchers string, client *channel.Client,
 ) ([]ExperimentIdentifierInPod, error) {
 	command := fmt.Sprintf("%s create %s %s %s", getTargetChaosBladeBin(expModel), expModel.Target, expModel.ActionName, matchers)
@@ -235,7 +220,7 @@ func generateCreateCommands(experimentId string, expModel *spec.ExpModel, contai
 			ContainerObjectMeta: containerObjectMetaList[idx],
 			Command:             command,
 		}
-		resp := deployChaosBlade(experimentId, expModel, obj, chaosBladeOverride, client)
+		resp := deployChaosBlade(ctx, experimentId, expModel, obj, chaosBladeOverride, client)
 		if !resp.Success {
 			identifierInPod.Error = resp.Err
 			identifierInPod.Code = resp.Code
@@ -19,6 +19,7 @@ package model
 import (
 	"context"
 	"fmt"
+	"hash/fnv"
 	"path"
 	"sync"
 
@@ -58,13 +59,31 @@ func (e *ExecCommandInPodExecutor) Name() string {
 func (e *ExecCommandInPodExecutor) SetChannel(channel spec.Channel) {
 }
 
+func (e *ExecCommandInPodExecutor) waitsStartBarrier() {}
+
 // execInMatchedPod will execute the experiment in the target pod
 func (e *ExecCommandInPodExecutor) Exec(uid string, ctx context.Context, expModel *spec.ExpModel) *spec.Response {
-	logrusField := logrus.WithField("experiment", GetExperimentIdFromContext(ctx))
+	experimentId := GetExperimentIdFromContext(ctx)
+	logrusField := logrus.WithField("experiment", experimentId)
 	experimentStatus := v1alpha1.ExperimentStatus{
 		ResStatuses: make([]v1alpha1.ResourceStatus, 0),
 	}
-	experimentIdentifiers, err := getExperimentIdentifiers(ctx, expModel, e.Client)
+	_, isDestroy := spec.IsDestroy(ctx)
+	retryPolicy, resp := GetExecRetryPolicy(expModel.ActionFlags, isDestroy)
+	if !resp.Success {
+		return spec.ResponseFailWithResult(spec.ParameterIllegal,
+			v1alpha1.CreateFailExperimentStatus(resp.Err, []v1alpha1.ResourceStatus{}),
+			resp.Err)
+	}
+	fanOutPolicy, resp := GetFanOutPolicy(expModel.ActionFlags)
+	if !resp.Success {
+		return spec.ResponseFailWithResult(spec.ParameterIllegal,
+			v1alpha1.CreateFailExperimentStatus(resp.Err, []v1alpha1.ResourceStatus{}),
+			resp.Err)
+	}
+	// the deployment and the status checks of the experiment share the rate limit with the commands
+	execCtx := fanOutPolicy.Context(ctx)
+	experimentIdentifiers, err := getExperimentIdentifiers(execCtx, expModel, e.Client)
 	if err != nil {
 		logrusField.Errorf("get experiment identifiers failed, err: %s", err.Error())
 		return spec.ResponseFailWithResult(spec.GetIdentifierFailed,
@@ -75,7 +94,6 @@ func (e *ExecCommandInPodExecutor) Exec(uid string, ctx context.Context, expMode
 
 	statuses := experimentStatus.ResStatuses
 	success := true
-	_, isDestroy := spec.IsDestroy(ctx)
 	updateResultLock := &sync.Mutex{}
 
 	execCommandInPod := func(i int) {
@@ -116,9 +134,27 @@ func (e *ExecCommandInPodExecutor) Exec(uid string, ctx context.Context, expMode
 				}
 			}
 		}
+		if execSuccess && !isDestroy {
+			// write ahead the intent, the injection is not recoverable without it
+			if err := recordJournal(ctx, e.Client, experimentId, expModel, rsStatus, JournalPendingState); err != nil {
+				logrusField.WithError(err).Errorln("record the injection intent failed")
+				rsStatus.CreateFailResourceStatus(fmt.Sprintf("record the injection intent failed, %v", err),
+					spec.K8sExecFailed.Code)
+				execSuccess = false
+			}
+		}
 		if execSuccess {
 			logrusField.Infof("execute identifier: %+v", identifier)
-			execSuccess, rsStatus = execCommands(isDestroy, rsStatus, identifier, e.Client)
+			execSuccess, rsStatus = execCommands(execCtx, isDestroy, rsStatus, identifier, e.Client, retryPolicy)
+			if !isDestroy {
+				state := JournalInjectedState
+				if !execSuccess {
+					state = JournalFailedState
+				}
+				if err := recordJournal(ctx, e.Client, experimentId, expModel, rsStatus, state); err != nil {
+					logrusField.WithError(err).Warnln("record the injection result failed")
+				}
+			}
 		}
 		updateResultLock.Lock()
 		statuses = append(statuses, rsStatus)
@@ -127,7 +163,8 @@ func (e *ExecCommandInPodExecutor) Exec(uid string, ctx context.Context, expMode
 		updateResultLock.Unlock()
 	}
 
-	ParallelizeExec(len(experimentIdentifiers), execCommandInPod)
+	// the destroy is not staggered to recover the targets as soon as possible
+	execAfterStartBarrier(ctx, isDestroy, fanOutPolicy, len(experimentIdentifiers), execCommandInPod)
 
 	logrusField.Infof("success: %t, statuses: %+v", success, statuses)
 	if success {
@@ -143,7 +180,7 @@ func (e *ExecCommandInPodExecutor) Exec(uid string, ctx context.Context, expMode
 	experimentStatus.Success = success
 	experimentStatus.ResStatuses = append(experimentStatus.ResStatuses, statuses...)
 
-	checkExperimentStatus(ctx, expModel, statuses, experimentIdentifiers, e.Client)
+	checkExperimentStatus(execCtx, expModel, statuses, experimentIdentifiers, e.Client)
 	return spec.ReturnResultIgnoreCode(experimentStatus)
 }
 
@@ -173,9 +210,9 @@ func getExperimentIdentifiers(ctx context.Context, expModel *spec.ExpModel, clie
 		return getDockerExperimentIdentifiers(experimentId, expModel, containerObjectMetaList, matchers, destroy, isContainerNetworkTarget, client)
 	}
 	if destroy {
-		return generateDestroyCommands(experimentId, expModel, containerObjectMetaList, matchers, client)
+		return generateDestroyCommands(ctx, experimentId, expModel, containerObjectMetaList, matchers, client)
 	}
-	return generateCreateCommands(experimentId, expModel, containerObjectMetaList, matchers, client)
+	return generateCreateCommands(ctx, experimentId, expModel, containerObjectMetaList, matchers, client)
 }
 
 func getDockerExperimentIdentifiers(experimentId string, expModel *spec.ExpModel,
@@ -328,9 +365,21 @@ func generateCreateCriCommands(experimentId string, expModel *spec.ExpModel,
 	return identifiers, nil
 }
 
-func deployChaosBlade(experimentId string, expModel *spec.ExpModel,
+// deployLocks serializes the deployments into the same container by the concurrent experiments
+var deployLocks [64]sync.Mutex
+
+func lockDeployment(obj ContainerObjectMeta) *sync.Mutex {
+	hash := fnv.New32a()
+	hash.Write([]byte(fmt.Sprintf("%s/%s/%s", obj.Namespace, obj.PodName, obj.ContainerName)))
+	return &deployLocks[hash.Sum32()%uint32(len(deployLocks))]
+}
+
+func deployChaosBlade(ctx context.Context, experimentId string, expModel *spec.ExpModel,
 	obj ContainerObjectMeta, override bool, client *channel.Client,
 ) *spec.Response {
+	lock := lockDeployment(obj)
+	lock.Lock()
+	defer lock.Unlock()
 	logrusField := logrus.WithField("experiment", experimentId)
 	chaosBladePath := getTargetChaosBladePath(expModel)
 	options := DeployOptions{
@@ -339,6 +388,14 @@ func deployChaosBlade(experimentId string, expModel *spec.ExpModel,
 		PodName:   obj.PodName,
 		client:    client,
 	}
+	if !override && options.HasToolkit(ctx, chaosBladePath) {
+		logrusField.WithFields(logrus.Fields{
+			"container": obj.ContainerName,
+			"pod":       obj.PodName,
+			"namespace": obj.Namespace,
+		}).Infof("chaosblade toolkit is preinstalled at %s, skip deploying", chaosBladePath)
+		return spec.Success()
+	}
 	deploy, err := getDeployMode(options, expModel)
 	if err != nil {
 		util.Errorf(experimentId, util.GetRunFuncName(), spec.ParameterLess.Sprintf(ChaosBladeDownloadUrlFlag.Name))
@@ -346,30 +403,30 @@ func deployChaosBlade(experimentId string, expModel *spec.ExpModel,
 	}
 	logrusField.Infof("deploy chaosblade under override with %t value", override)
 	chaosBladeBinPath := path.Join(chaosBladePath, "bin")
-	if err := options.CheckFileExists(chaosBladeBinPath); err != nil {
+	if err := options.CheckFileExists(ctx, chaosBladeBinPath); err != nil {
 		// create chaosblade path
-		if err := options.CreateDir(chaosBladeBinPath); err != nil {
+		if err := options.CreateDir(ctx, chaosBladeBinPath); err != nil {
 			util.Errorf(experimentId, util.GetRunFuncName(), fmt.Sprintf("create chaosblade dir: %s, failed! err: %s", chaosBladeBinPath, err.Error()))
 			return spec.ResponseFailWithFlags(spec.ParameterInvalidBladePathError, ChaosBladePathFlag.Name, chaosBladeBinPath, err)
 		}
 	}
 	bladePath := path.Join(chaosBladePath, "blade")
-	if override || options.CheckFileExists(bladePath) != nil {
-		if err := deploy.DeployToPod(experimentId, chaosblade.OperatorChaosBladeBlade, bladePath); err != nil {
+	if override || options.CheckFileExists(ctx, bladePath) != nil {
+		if err := deploy.DeployToPod(ctx, experimentId, chaosblade.OperatorChaosBladeBlade, bladePath); err != nil {
 			util.Errorf(experimentId, util.GetRunFuncName(), fmt.Sprintf("deploy blade failed! dir: %s, err: %s", bladePath, err.Error()))
 			return spec.ResponseFailWithFlags(spec.DeployChaosBladeFailed, bladePath, err)
 		}
 	}
 	yamlPath := path.Join(chaosBladePath, "yaml")
-	if override || options.CheckFileExists(yamlPath) != nil {
-		if err := deploy.DeployToPod(experimentId, chaosblade.OperatorChaosBladeYaml, yamlPath); err != nil {
+	if override || options.CheckFileExists(ctx, yamlPath) != nil {
+		if err := deploy.DeployToPod(ctx, experimentId, chaosblade.OperatorChaosBladeYaml, yamlPath); err != nil {
 			util.Errorf(experimentId, util.GetRunFuncName(), fmt.Sprintf("deploy yaml failed! dir: %s, err: %s", yamlPath, err.Error()))
 			return spec.ResponseFailWithFlags(spec.DeployChaosBladeFailed, yamlPath, err)
 		}
 	}
 	chaosOSPath := path.Join(chaosBladePath, "bin", "chaos_os")
-	if override || options.CheckFileExists(chaosOSPath) != nil {
-		if err := deploy.DeployToPod(experimentId, path.Join(chaosblade.OperatorChaosBladeBin, "chaos_os"), chaosOSPath); err != nil {
+	if override || options.CheckFileExists(ctx, chaosOSPath) != nil {
+		if err := deploy.DeployToPod(ctx, experimentId, path.Join(chaosblade.OperatorChaosBladeBin, "chaos_os"), chaosOSPath); err != nil {
 			util.Errorf(experimentId, util.GetRunFuncName(), fmt.Sprintf("deploy chaos_os failed! dir: %s, err: %s", chaosOSPath, err.Error()))
 			return spec.ResponseFailWithFlags(spec.DeployChaosBladeFailed, chaosOSPath, err)
 		}
@@ -385,11 +442,11 @@ func deployChaosBlade(experimentId string, expModel *spec.ExpModel,
 			programFile = path.Join(chaosBladePath, "bin", program)
 			operatorProgramFile = path.Join(chaosblade.OperatorChaosBladeBin, program)
 		}
-		if !override && options.CheckFileExists(programFile) == nil {
+		if !override && options.CheckFileExists(ctx, programFile) == nil {
 			logrusField.WithField("program", programFile).Infof("program exists")
 			continue
 		}
-		err := deploy.DeployToPod(experimentId, operatorProgramFile, programFile)
+		err := deploy.DeployToPod(ctx, experimentId, operatorProgramFile, programFile)
 		logrusField = logrusField.WithFields(logrus.Fields{
 			"container": obj.ContainerName,
 			"pod":       obj.PodName,
@@ -45,12 +45,30 @@ func (e *CommonExecutor) Name() string {
 func (e *CommonExecutor) SetChannel(channel spec.Channel) {
 }
 
+func (e *CommonExecutor) waitsStartBarrier() {}
+
 func (e *CommonExecutor) Exec(uid string, ctx context.Context, expModel *spec.ExpModel) *spec.Response {
-	logrusField := logrus.WithField("experiment", GetExperimentIdFromContext(ctx))
+	experimentId := GetExperimentIdFromContext(ctx)
+	logrusField := logrus.WithField("experiment", experimentId)
 	experimentStatus := v1alpha1.ExperimentStatus{
 		ResStatuses: make([]v1alpha1.ResourceStatus, 0),
 	}
-	experimentIdentifiers, err := getExperimentIdentifiersWithNsexec(ctx, expModel, e.Client)
+	_, isDestroy := spec.IsDestroy(ctx)
+	retryPolicy, resp := GetExecRetryPolicy(expModel.ActionFlags, isDestroy)
+	if !resp.Success {
+		return spec.ResponseFailWithResult(spec.ParameterIllegal,
+			v1alpha1.CreateFailExperimentStatus(resp.Err, []v1alpha1.ResourceStatus{}),
+			resp.Err)
+	}
+	fanOutPolicy, resp := GetFanOutPolicy(expModel.ActionFlags)
+	if !resp.Success {
+		return spec.ResponseFailWithResult(spec.ParameterIllegal,
+			v1alpha1.CreateFailExperimentStatus(resp.Err, []v1alpha1.ResourceStatus{}),
+			resp.Err)
+	}
+	// the deployment and the status checks of the experiment share the rate limit with the commands
+	execCtx := fanOutPolicy.Context(ctx)
+	experimentIdentifiers, err := getExperimentIdentifiersWithNsexec(execCtx, expModel, e.Client)
 	if err != nil {
 		logrusField.Errorf("get experiment identifiers failed, err: %s", err.Error())
 		return spec.ResponseFailWithResult(spec.GetIdentifierFailed,
@@ -61,7 +79,6 @@ func (e *CommonExecutor) Exec(uid string, ctx context.Context, expModel *spec.Ex
 
 	statuses := experimentStatus.ResStatuses
 	success := true
-	_, isDestroy := spec.IsDestroy(ctx)
 	updateResultLock := &sync.Mutex{}
 
 	execCommandInPod := func(i int) {
@@ -102,9 +119,27 @@ func (e *CommonExecutor) Exec(uid string, ctx context.Context, expModel *spec.Ex
 				}
 			}
 		}
+		if execSuccess && !isDestroy {
+			// write ahead the intent, the injection is not recoverable without it
+			if err := recordJournal(ctx, e.Client, experimentId, expModel, rsStatus, JournalPendingState); err != nil {
+				logrusField.WithError(err).Errorln("record the injection intent failed")
+				rsStatus.CreateFailResourceStatus(fmt.Sprintf("record the injection intent failed, %v", err),
+					spec.K8sExecFailed.Code)
+				execSuccess = false
+			}
+		}
 		if execSuccess {
 			logrusField.Infof("execute identifier: %+v", identifier)
-			execSuccess, rsStatus = execCommands(isDestroy, rsStatus, identifier, e.Client)
+			execSuccess, rsStatus = execCommands(execCtx, isDestroy, rsStatus, identifier, e.Client, retryPolicy)
+			if !isDestroy {
+				state := JournalInjectedState
+				if !execSuccess {
+					state = JournalFailedState
+				}
+				if err := recordJournal(ctx, e.Client, experimentId, expModel, rsStatus, state); err != nil {
+					logrusField.WithError(err).Warnln("record the injection result failed")
+				}
+			}
 		}
 		updateResultLock.Lock()
 		statuses = append(statuses, rsStatus)
@@ -113,7 +148,8 @@ func (e *CommonExecutor) Exec(uid string, ctx context.Context, expModel *spec.Ex
 		updateResultLock.Unlock()
 	}
 
-	ParallelizeExec(len(experimentIdentifiers), execCommandInPod)
+	// the destroy is not staggered to recover the targets as soon as possible
+	execAfterStartBarrier(ctx, isDestroy, fanOutPolicy, len(experimentIdentifiers), execCommandInPod)
 
 	logrusField.Infof("success: %t, statuses: %+v", success, statuses)
 	if success {
@@ -129,7 +165,7 @@ func (e *CommonExecutor) Exec(uid string, ctx context.Context, expModel *spec.Ex
 	experimentStatus.Success = success
 	experimentStatus.ResStatuses = append(experimentStatus.ResStatuses, statuses...)
 
-	checkExperimentStatus(ctx, expModel, statuses, experimentIdentifiers, e.Client)
+	checkExperimentStatus(execCtx, expModel, statuses, experimentIdentifiers, e.Client)
 	return spec.ReturnResultIgnoreCode(experimentStatus)
 }
 
@@ -0,0 +1,115 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade-operator/channel"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/runtime/chaosblade"
+)
+
+// FanOutPolicy controls how the experiment is executed across the targets
+type FanOutPolicy struct {
+	// Concurrency is the max number of the targets executed concurrently
+	Concurrency int
+	// QPS and Burst are the token bucket of the exec calls, no limit if QPS is 0
+	QPS   float32
+	Burst int
+	// Stagger is the window to spread the executions across the targets evenly, at once if 0
+	Stagger time.Duration
+}
+
+// GetFanOutPolicy returns the fan-out policy of the experiment, the flags of the experiment override the
+// defaults of the operator
+func GetFanOutPolicy(flags map[string]string) (FanOutPolicy, *spec.Response) {
+	policy := FanOutPolicy{
+		Concurrency: chaosblade.ExecConcurrency,
+		QPS:         chaosblade.ExecQPS,
+		Burst:       chaosblade.ExecBurst,
+	}
+	if value := flags[ExecConcurrencyFlag.Name]; value != "" {
+		concurrency, err := strconv.Atoi(value)
+		if err != nil || concurrency < 1 {
+			return policy, spec.ResponseFailWithFlags(spec.ParameterIllegal, ExecConcurrencyFlag.Name, value,
+				"it must be a positive integer")
+		}
+		policy.Concurrency = concurrency
+	}
+	if value := flags[ExecQPSFlag.Name]; value != "" {
+		qps, err := strconv.ParseFloat(value, 32)
+		if err != nil || qps < 0 {
+			return policy, spec.ResponseFailWithFlags(spec.ParameterIllegal, ExecQPSFlag.Name, value,
+				"it must be a non-negative number")
+		}
+		policy.QPS = float32(qps)
+	}
+	if value := flags[ExecBurstFlag.Name]; value != "" {
+		burst, err := strconv.Atoi(value)
+		if err != nil || burst < 1 {
+			return policy, spec.ResponseFailWithFlags(spec.ParameterIllegal, ExecBurstFlag.Name, value,
+				"it must be a positive integer")
+		}
+		policy.Burst = burst
+	}
+	if value := flags[ExecStaggerFlag.Name]; value != "" {
+		stagger, err := time.ParseDuration(value)
+		if err != nil || stagger < 0 {
+			return policy, spec.ResponseFailWithFlags(spec.ParameterIllegal, ExecStaggerFlag.Name, value,
+				"it must be a non-negative duration such as 1m")
+		}
+		policy.Stagger = stagger
+	}
+	return policy, spec.Success()
+}
+
+// Context returns the context limiting the exec calls of the experiment by the rate limit of the policy
+func (p FanOutPolicy) Context(ctx context.Context) context.Context {
+	return channel.WithRateLimiter(ctx, p.QPS, p.Burst)
+}
+
+// staggerDelay returns the offset of the work from the start, the works are spread over the stagger window
+// in order
+func (p FanOutPolicy) staggerDelay(workID, workCount int) time.Duration {
+	if p.Stagger <= 0 || workCount <= 1 {
+		return 0
+	}
+	return time.Duration(int64(p.Stagger) * int64(workID) / int64(workCount))
+}
+
+// Exec does the works by the concurrency of the policy, and spreads the starts of the works over the stagger
+// window if stagger is true
+func (p FanOutPolicy) Exec(ctx context.Context, workCount int, stagger bool, doWork DoWorkFunc) {
+	start := time.Now()
+	ParallelizeExecWithWorkers(p.Concurrency, workCount, func(workID int) {
+		if stagger {
+			if delay := time.Until(start.Add(p.staggerDelay(workID, workCount))); delay > 0 {
+				timer := time.NewTimer(delay)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+				}
+			}
+		}
+		doWork(workID)
+	})
+}
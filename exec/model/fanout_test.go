@@ -0,0 +1,79 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetFanOutPolicy(t *testing.T) {
+	policy, resp := GetFanOutPolicy(map[string]string{
+		ExecConcurrencyFlag.Name: "5",
+		ExecQPSFlag.Name:         "0.5",
+		ExecBurstFlag.Name:       "2",
+		ExecStaggerFlag.Name:     "1m",
+	})
+	if !resp.Success {
+		t.Fatalf("GetFanOutPolicy() failed, %s", resp.Err)
+	}
+	if policy.Concurrency != 5 || policy.QPS != 0.5 || policy.Burst != 2 || policy.Stagger != time.Minute {
+		t.Errorf("unexpected policy %+v", policy)
+	}
+	for _, flags := range []map[string]string{
+		{ExecConcurrencyFlag.Name: "0"},
+		{ExecQPSFlag.Name: "-1"},
+		{ExecBurstFlag.Name: "many"},
+		{ExecStaggerFlag.Name: "1"},
+	} {
+		if _, resp := GetFanOutPolicy(flags); resp.Success {
+			t.Errorf("expected the illegal flags %v failed", flags)
+		}
+	}
+}
+
+func TestFanOutPolicy_Exec(t *testing.T) {
+	policy := FanOutPolicy{Concurrency: 2, Stagger: 100 * time.Millisecond}
+	if got := policy.staggerDelay(3, 4); got != 75*time.Millisecond {
+		t.Errorf("staggerDelay() = %s, want 75ms", got)
+	}
+	var lock sync.Mutex
+	running, maxRunning := 0, 0
+	starts := make([]time.Duration, 4)
+	start := time.Now()
+	policy.Exec(context.Background(), len(starts), true, func(workID int) {
+		lock.Lock()
+		starts[workID] = time.Since(start)
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		lock.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		lock.Lock()
+		running--
+		lock.Unlock()
+	})
+	if maxRunning > policy.Concurrency {
+		t.Errorf("expected at most %d works running concurrently, got %d", policy.Concurrency, maxRunning)
+	}
+	if starts[3] < 75*time.Millisecond {
+		t.Errorf("expected the last work started after 75ms, started at %s", starts[3])
+	}
+}
@@ -0,0 +1,243 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/chaosblade-io/chaosblade-operator/channel"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/apis/chaosblade/v1alpha1"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/runtime/chaosblade"
+)
+
+// The journal is a configmap per chaosblade in the operator namespace. The spec of the chaosblade is written
+// before the experiments are created, and each target is written before and after the chaosblade command is
+// executed in it. The journal is deleted after the status of the chaosblade is updated, so the injections
+// are recoverable if the operator restarts before that. The journals are read and written by the clientset,
+// so the configmaps are not cached by the operator.
+const (
+	JournalLabel = "chaosblade.io/journal"
+	// JournalBladeAnnotation is the name of the chaosblade, which may be too long for a label value
+	JournalBladeAnnotation = "chaosblade.io/blade-name"
+	JournalNamePrefix      = "chaosblade-journal-"
+	journalSpecKey         = "spec"
+	journalEntryKey        = "entry."
+)
+
+const (
+	// JournalPendingState is the target before the command is executed, the result is unknown if the
+	// operator restarts in this state
+	JournalPendingState  = "Pending"
+	JournalInjectedState = "Injected"
+	JournalFailedState   = "Failed"
+)
+
+// JournalEntry is the injection intent and result of a target
+type JournalEntry struct {
+	// Name is the name of the experiment in the chaosblade, empty in the entries written by the older operator
+	Name       string      `json:"name,omitempty"`
+	Scope      string      `json:"scope"`
+	Target     string      `json:"target"`
+	Action     string      `json:"action"`
+	Kind       string      `json:"kind"`
+	Identifier string      `json:"identifier"`
+	Id         string      `json:"id,omitempty"`
+	State      string      `json:"state"`
+	Error      string      `json:"error,omitempty"`
+	Time       metav1.Time `json:"time"`
+}
+
+// Journal is the injections of a chaosblade recorded in the journal configmap
+type Journal struct {
+	BladeName string
+	// Spec is the spec of the chaosblade when the experiments are created, nil if not recorded
+	Spec    *v1alpha1.ChaosBladeSpec
+	Entries []JournalEntry
+}
+
+// ResourceStatus returns the resource status of the injected target
+func (e *JournalEntry) ResourceStatus() v1alpha1.ResourceStatus {
+	rsStatus := v1alpha1.ResourceStatus{
+		Kind:       e.Kind,
+		Identifier: e.Identifier,
+		Id:         e.Id,
+	}
+	if e.State == JournalInjectedState {
+		return rsStatus.CreateSuccessResourceStatus()
+	}
+	return rsStatus.CreateFailResourceStatus(e.Error, spec.K8sExecFailed.Code)
+}
+
+// Matches returns true if the entry is the target of the experiment, the entry without the name matches the
+// experiments with the same scope, target and action
+func (e *JournalEntry) Matches(expSpec v1alpha1.ExperimentSpec) bool {
+	if e.Name != "" && expSpec.Name != "" && e.Name != expSpec.Name {
+		return false
+	}
+	return e.Scope == expSpec.Scope && e.Target == expSpec.Target && e.Action == expSpec.Action
+}
+
+func (e *JournalEntry) key() string {
+	hash := fnv.New64a()
+	hash.Write([]byte(strings.Join([]string{e.Name, e.Scope, e.Target, e.Action, e.Identifier}, "/")))
+	return fmt.Sprintf("%s%016x", journalEntryKey, hash.Sum64())
+}
+
+// JournalName returns the name of the journal configmap of the chaosblade, the long name is shortened by hash
+func JournalName(bladeName string) string {
+	name := JournalNamePrefix + bladeName
+	if len(name) <= validation.DNS1123SubdomainMaxLength {
+		return name
+	}
+	hash := fnv.New64a()
+	hash.Write([]byte(bladeName))
+	return fmt.Sprintf("%s-%016x", name[:validation.DNS1123SubdomainMaxLength-17], hash.Sum64())
+}
+
+// BeginJournal writes the spec of the chaosblade to the journal before the experiments are created
+func BeginJournal(ctx context.Context, client *channel.Client, cb *v1alpha1.ChaosBlade) error {
+	if !chaosblade.JournalEnable {
+		return nil
+	}
+	bladeSpec, err := json.Marshal(cb.Spec)
+	if err != nil {
+		return err
+	}
+	return patchJournal(ctx, client, cb.Name, journalSpecKey, string(bladeSpec))
+}
+
+// CommitJournal deletes the journal after the injections are recorded in the status of the chaosblade
+func CommitJournal(ctx context.Context, client *channel.Client, bladeName string) error {
+	if !chaosblade.JournalEnable {
+		return nil
+	}
+	err := client.CoreV1().ConfigMaps(chaosblade.DaemonsetPodNamespace).Delete(ctx, JournalName(bladeName),
+		metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// ListJournals returns the journals not committed
+func ListJournals(ctx context.Context, client *channel.Client) ([]Journal, error) {
+	configMaps, err := client.CoreV1().ConfigMaps(chaosblade.DaemonsetPodNamespace).List(ctx,
+		metav1.ListOptions{LabelSelector: JournalLabel})
+	if err != nil {
+		return nil, err
+	}
+	journals := make([]Journal, 0, len(configMaps.Items))
+	for _, configMap := range configMaps.Items {
+		journal, err := parseJournal(&configMap)
+		if err != nil {
+			return nil, fmt.Errorf("parse journal %s error, %v", configMap.Name, err)
+		}
+		journals = append(journals, journal)
+	}
+	return journals, nil
+}
+
+func parseJournal(configMap *v1.ConfigMap) (Journal, error) {
+	journal := Journal{
+		BladeName: configMap.Annotations[JournalBladeAnnotation],
+		Entries:   make([]JournalEntry, 0),
+	}
+	for key, value := range configMap.Data {
+		switch {
+		case key == journalSpecKey:
+			bladeSpec := &v1alpha1.ChaosBladeSpec{}
+			if err := json.Unmarshal([]byte(value), bladeSpec); err != nil {
+				return journal, err
+			}
+			journal.Spec = bladeSpec
+		case strings.HasPrefix(key, journalEntryKey):
+			entry := JournalEntry{}
+			if err := json.Unmarshal([]byte(value), &entry); err != nil {
+				return journal, err
+			}
+			journal.Entries = append(journal.Entries, entry)
+		}
+	}
+	return journal, nil
+}
+
+// recordJournal writes the intent or the result of the target to the journal
+func recordJournal(ctx context.Context, client *channel.Client, bladeName string, expModel *spec.ExpModel,
+	rsStatus v1alpha1.ResourceStatus, state string,
+) error {
+	if !chaosblade.JournalEnable {
+		return nil
+	}
+	entry := JournalEntry{
+		Name:       GetExperimentNameFromContext(ctx),
+		Scope:      expModel.Scope,
+		Target:     expModel.Target,
+		Action:     expModel.ActionName,
+		Kind:       rsStatus.Kind,
+		Identifier: rsStatus.Identifier,
+		Id:         rsStatus.Id,
+		State:      state,
+		Error:      rsStatus.Error,
+		Time:       metav1.Now(),
+	}
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return patchJournal(ctx, client, bladeName, entry.key(), string(value))
+}
+
+// patchJournal sets the key of the journal by a merge patch, so the targets executed in parallel are
+// written without conflicts. The journal is created if not exists.
+func patchJournal(ctx context.Context, client *channel.Client, bladeName, key, value string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"data": map[string]string{key: value},
+	})
+	if err != nil {
+		return err
+	}
+	configMaps := client.CoreV1().ConfigMaps(chaosblade.DaemonsetPodNamespace)
+	name := JournalName(bladeName)
+	_, err = configMaps.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+	_, err = configMaps.Create(ctx, &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   chaosblade.DaemonsetPodNamespace,
+			Labels:      map[string]string{JournalLabel: "true"},
+			Annotations: map[string]string{JournalBladeAnnotation: bladeName},
+		},
+		Data: map[string]string{key: value},
+	}, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		_, err = configMaps.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	}
+	return err
+}
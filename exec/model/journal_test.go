@@ -0,0 +1,85 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/chaosblade-io/chaosblade-operator/channel"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/apis/chaosblade/v1alpha1"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/runtime/chaosblade"
+)
+
+func TestJournal(t *testing.T) {
+	client := &channel.Client{Interface: fake.NewSimpleClientset()}
+	chaosblade.JournalEnable = true
+
+	cb := &v1alpha1.ChaosBlade{
+		ObjectMeta: metav1.ObjectMeta{Name: "cpu-load"},
+		Spec: v1alpha1.ChaosBladeSpec{Experiments: []v1alpha1.ExperimentSpec{
+			{Scope: "pod", Target: "cpu", Action: "fullload"},
+		}},
+	}
+	ctx := context.Background()
+	if err := BeginJournal(ctx, client, cb); err != nil {
+		t.Fatalf("begin journal failed, %v", err)
+	}
+	expModel := &spec.ExpModel{Scope: "pod", Target: "cpu", ActionName: "fullload"}
+	loadCtx := SetExperimentNameToContext(ctx, "load")
+	for _, identifier := range []string{"default/node1/pod1", "default/node2/pod2"} {
+		rsStatus := v1alpha1.ResourceStatus{Kind: "pod", Identifier: identifier}
+		if err := recordJournal(loadCtx, client, cb.Name, expModel, rsStatus, JournalPendingState); err != nil {
+			t.Fatalf("record intent failed, %v", err)
+		}
+	}
+	rsStatus := v1alpha1.ResourceStatus{Kind: "pod", Identifier: "default/node1/pod1", Id: "7c1f7afc281482c8"}
+	if err := recordJournal(loadCtx, client, cb.Name, expModel, rsStatus.CreateSuccessResourceStatus(), JournalInjectedState); err != nil {
+		t.Fatalf("record result failed, %v", err)
+	}
+	// the experiment with another name in the same target is recorded separately
+	rsStatus = v1alpha1.ResourceStatus{Kind: "pod", Identifier: "default/node1/pod1"}
+	if err := recordJournal(SetExperimentNameToContext(ctx, "load-2"), client, cb.Name, expModel, rsStatus, JournalPendingState); err != nil {
+		t.Fatalf("record intent failed, %v", err)
+	}
+
+	journals, err := ListJournals(ctx, client)
+	if err != nil || len(journals) != 1 {
+		t.Fatalf("expected 1 journal, got %v, %v", journals, err)
+	}
+	journal := journals[0]
+	if journal.BladeName != cb.Name || journal.Spec == nil || len(journal.Spec.Experiments) != 1 || len(journal.Entries) != 3 {
+		t.Fatalf("unexpected journal %+v", journal)
+	}
+	for _, entry := range journal.Entries {
+		injected := entry.Name == "load" && entry.Identifier == "default/node1/pod1"
+		if injected != (entry.State == JournalInjectedState) || injected != (entry.Id != "") {
+			t.Errorf("unexpected entry %+v", entry)
+		}
+	}
+
+	if err := CommitJournal(ctx, client, cb.Name); err != nil {
+		t.Fatalf("commit journal failed, %v", err)
+	}
+	if journals, err := ListJournals(ctx, client); err != nil || len(journals) != 0 {
+		t.Errorf("expected the journal deleted, got %v, %v", journals, err)
+	}
+}
@@ -150,6 +150,17 @@ var ResourceGroupKeyFlag = &spec.ExpFlag{
 	Required: false,
 }
 
+var StickyFlag = &spec.ExpFlag{
+	Name:   "sticky",
+	Desc:   "Inject into the new pods matching the namespace, labels or names for the remaining duration, such as the replicas recreated after the targets are rescheduled. The evict-count and evict-percent are applied to the current matching pods. It requires the sticky-enable of the operator",
+	NoArgs: true,
+}
+
+var SuccessPolicyFlag = &spec.ExpFlag{
+	Name: "success-policy",
+	Desc: "The policy to judge the experiment successful by the targets, all, any or the min percent of the successful targets such as 80%. The default value is success-policy of the operator",
+}
+
 var ContainerIdsFlag = &spec.ExpFlag{
 	Name:     "container-ids",
 	Desc:     "Container ids",
@@ -189,6 +200,36 @@ var ChaosBladeDeployModeFlag = &spec.ExpFlag{
 	Desc: "The mode of chaosblade deployment in container, the values are copy and download, the default value is copy which copy tool from the operator to the target container. If you select download mode, the operator will download chaosblade tool from the chaosblade-download-url.",
 }
 
+var ExecRetryAttemptsFlag = &spec.ExpFlag{
+	Name: "exec-retry-attempts",
+	Desc: "The max attempts of the chaosblade commands failed before they start, such as the kubelet restart, the dial failure or the container not started yet. No retry if 1, the default value is exec-retry-attempts of the operator for the destroy commands and 1 for the create commands",
+}
+
+var ExecRetryBackoffFlag = &spec.ExpFlag{
+	Name: "exec-retry-backoff",
+	Desc: "The wait before the first retry of the chaosblade commands, such as 1s, doubled after each retry. The default value is exec-retry-backoff of the operator",
+}
+
+var ExecConcurrencyFlag = &spec.ExpFlag{
+	Name: "exec-concurrency",
+	Desc: "The max number of the targets executed concurrently. The default value is exec-concurrency of the operator",
+}
+
+var ExecQPSFlag = &spec.ExpFlag{
+	Name: "exec-qps",
+	Desc: "The max exec calls per second of the experiment, such as 5 or 0.5, including the retries. The default value is exec-qps of the operator",
+}
+
+var ExecBurstFlag = &spec.ExpFlag{
+	Name: "exec-burst",
+	Desc: "The max burst of the exec calls limited by exec-qps. The default value is exec-burst of the operator",
+}
+
+var ExecStaggerFlag = &spec.ExpFlag{
+	Name: "exec-stagger",
+	Desc: "The window to spread the injections across the targets evenly, such as 1m, the targets are injected at once if empty. Not used when destroying",
+}
+
 var IsDockerNetworkFlag = &spec.ExpFlag{
 	Name:     "is-docker-network",
 	Desc:     "Used when a docker container is used and there is no tc command in the target container. Just for docker command, Deprecated！ Please use use-sidecar-container-network flag.",
@@ -224,6 +265,8 @@ func GetResourceCommonFlags() []spec.ExpFlagSpec {
 		ResourceNamespaceFlag,
 		ResourceLabelsFlag,
 		ResourceGroupKeyFlag,
+		StickyFlag,
+		SuccessPolicyFlag,
 	}
 }
 
@@ -233,6 +276,12 @@ func GetChaosBladeFlags() []spec.ExpFlagSpec {
 		exec.ChaosBladeOverrideFlag,
 		ChaosBladeDeployModeFlag,
 		ChaosBladeDownloadUrlFlag,
+		ExecRetryAttemptsFlag,
+		ExecRetryBackoffFlag,
+		ExecConcurrencyFlag,
+		ExecQPSFlag,
+		ExecBurstFlag,
+		ExecStaggerFlag,
 	}
 }
 
@@ -243,6 +292,8 @@ func GetResourceFlagNames() map[string]spec.Empty {
 		ResourceNamesFlag.Name,
 		ResourceNamespaceFlag.Name,
 		ResourceLabelsFlag.Name,
+		StickyFlag.Name,
+		SuccessPolicyFlag.Name,
 		ContainerIdsFlag.Name,
 		ContainerNamesFlag.Name,
 		ContainerIndexFlag.Name,
@@ -250,6 +301,12 @@ func GetResourceFlagNames() map[string]spec.Empty {
 		exec.ChaosBladeOverrideFlag.Name,
 		ChaosBladeDeployModeFlag.Name,
 		ChaosBladeDownloadUrlFlag.Name,
+		ExecRetryAttemptsFlag.Name,
+		ExecRetryBackoffFlag.Name,
+		ExecConcurrencyFlag.Name,
+		ExecQPSFlag.Name,
+		ExecBurstFlag.Name,
+		ExecStaggerFlag.Name,
 		IsDockerNetworkFlag.Name,
 		UseSidecarContainerNetworkFlag.Name,
 	}
@@ -16,16 +16,24 @@
 
 package model
 
-import "sync"
+import (
+	"sync"
 
-const (
-	maxWorkers = 64 // magic number
+	"github.com/chaosblade-io/chaosblade-operator/pkg/runtime/chaosblade"
 )
 
 type DoWorkFunc func(workID int)
 
+// ParallelizeExec does the works by the exec-concurrency of the operator
 func ParallelizeExec(workCount int, doWork DoWorkFunc) {
-	workers := maxWorkers
+	ParallelizeExecWithWorkers(chaosblade.ExecConcurrency, workCount, doWork)
+}
+
+// ParallelizeExecWithWorkers does the works by the max workers concurrently
+func ParallelizeExecWithWorkers(workers, workCount int, doWork DoWorkFunc) {
+	if workers < 1 {
+		workers = 1
+	}
 	toExec := make(chan int, workCount)
 
 	for i := 0; i < workCount; i++ {
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade-operator/pkg/apis/chaosblade/v1alpha1"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/runtime/chaosblade"
+)
+
+const (
+	SuccessPolicyAll = "all"
+	SuccessPolicyAny = "any"
+)
+
+// SuccessPolicy decides whether the experiment is successful by the results of the targets
+type SuccessPolicy struct {
+	// MinPercent is the min percent of the successful targets, at least one target must be successful if 0
+	MinPercent int
+}
+
+// ParseSuccessPolicy parses the policy, the value is all, any or the min percent such as 80%
+func ParseSuccessPolicy(value string) (SuccessPolicy, error) {
+	switch value {
+	case SuccessPolicyAll:
+		return SuccessPolicy{MinPercent: 100}, nil
+	case SuccessPolicyAny:
+		return SuccessPolicy{}, nil
+	}
+	percent, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+	if err != nil {
+		return SuccessPolicy{}, fmt.Errorf("it must be all, any or a percent such as 80%%")
+	}
+	if percent <= 0 || percent > 100 {
+		return SuccessPolicy{}, fmt.Errorf("the percent must be in (0, 100]")
+	}
+	return SuccessPolicy{MinPercent: percent}, nil
+}
+
+// GetSuccessPolicy returns the policy of the experiment, the success-policy of the operator is used if the
+// experiment does not specify it
+func GetSuccessPolicy(flags map[string]string) (SuccessPolicy, *spec.Response) {
+	name, value := SuccessPolicyFlag.Name, flags[SuccessPolicyFlag.Name]
+	if value == "" {
+		name, value = "success-policy of the operator", chaosblade.SuccessPolicy
+	}
+	policy, err := ParseSuccessPolicy(value)
+	if err != nil {
+		return policy, spec.ResponseFailWithFlags(spec.ParameterIllegal, name, value, err)
+	}
+	return policy, spec.Success()
+}
+
+// Satisfied returns true if the successful targets are enough, false is returned if no target is found
+func (p SuccessPolicy) Satisfied(statuses []v1alpha1.ResourceStatus) bool {
+	succeeded := 0
+	for _, status := range statuses {
+		if status.Success {
+			succeeded++
+		}
+	}
+	if succeeded == 0 {
+		return false
+	}
+	return succeeded*100 >= p.MinPercent*len(statuses)
+}
+
+// Apply judges the created experiment by the results of the targets, the experiment failed before executing
+// in the targets is not changed
+func (p SuccessPolicy) Apply(expStatus v1alpha1.ExperimentStatus) v1alpha1.ExperimentStatus {
+	if len(expStatus.ResStatuses) == 0 {
+		return expStatus
+	}
+	expStatus.Success = p.Satisfied(expStatus.ResStatuses)
+	if expStatus.Success {
+		expStatus.State = v1alpha1.SuccessState
+		expStatus.Error = ""
+	} else {
+		expStatus.State = v1alpha1.ErrorState
+		expStatus.Error = "the successful targets are not enough for the success policy, see resStatuses for the error details"
+	}
+	return expStatus
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-operator/pkg/apis/chaosblade/v1alpha1"
+)
+
+func TestSuccessPolicy(t *testing.T) {
+	statuses := []v1alpha1.ResourceStatus{{Success: true}, {Success: true}, {Success: true}, {Success: false}}
+	tests := []struct {
+		value   string
+		want    bool
+		wantErr bool
+	}{
+		{value: "all", want: false},
+		{value: "any", want: true},
+		{value: "75%", want: true},
+		{value: "80", want: false},
+		{value: "0%", wantErr: true},
+		{value: "most", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			policy, err := ParseSuccessPolicy(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSuccessPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := policy.Satisfied(statuses); got != tt.want {
+				t.Errorf("Satisfied() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	anyPolicy, _ := ParseSuccessPolicy(SuccessPolicyAny)
+	if anyPolicy.Satisfied([]v1alpha1.ResourceStatus{{Success: false}}) {
+		t.Errorf("no successful target must not satisfy the any policy")
+	}
+}
@@ -0,0 +1,199 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade-operator/channel"
+)
+
+// BladeResponse is the json response printed by the chaosblade tool, the result depends on the command
+type BladeResponse struct {
+	Code    int32           `json:"code"`
+	Success bool            `json:"success"`
+	Err     string          `json:"error,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+}
+
+// BladeStatus is the result of the status command of the chaosblade tool
+type BladeStatus struct {
+	Uid        string `json:"Uid"`
+	Command    string `json:"Command"`
+	SubCommand string `json:"SubCommand"`
+	Flag       string `json:"Flag"`
+	Status     string `json:"Status"`
+	Error      string `json:"Error"`
+	CreateTime string `json:"CreateTime"`
+	UpdateTime string `json:"UpdateTime"`
+}
+
+var errNoBladeResponse = errors.New("no chaosblade response found in the output")
+
+// ParseBladeResponse finds the chaosblade response in the output. The logs or warnings printed around the
+// response are skipped, and the last response is used if there are several ones.
+func ParseBladeResponse(output []byte) (*BladeResponse, error) {
+	var response *BladeResponse
+	var lastErr error = errNoBladeResponse
+	for i := 0; i < len(output); {
+		start := bytes.IndexByte(output[i:], '{')
+		if start < 0 {
+			break
+		}
+		start += i
+		decoder := json.NewDecoder(bytes.NewReader(output[start:]))
+		fields := make(map[string]json.RawMessage)
+		if err := decoder.Decode(&fields); err != nil {
+			// a partial response is the output cut by the exec deadline or the max output bytes
+			if response == nil && isBladeResponseStart(output[start:]) {
+				lastErr = fmt.Errorf("incomplete chaosblade response, %v", err)
+			}
+			i = start + 1
+			continue
+		}
+		end := start + int(decoder.InputOffset())
+		if _, ok := fields["code"]; ok {
+			candidate := &BladeResponse{}
+			if err := json.Unmarshal(output[start:end], candidate); err != nil {
+				lastErr = err
+			} else {
+				response = candidate
+			}
+		}
+		i = end
+	}
+	if response == nil {
+		return nil, lastErr
+	}
+	return response, nil
+}
+
+// isBladeResponseStart returns true if the output starts with the code or success field of the response
+func isBladeResponseStart(output []byte) bool {
+	prefix := strings.Join(strings.Fields(string(output[:min(len(output), 16)])), "")
+	return strings.HasPrefix(prefix, `{"code"`) || strings.HasPrefix(prefix, `{"success"`)
+}
+
+// ParseCreateResponse returns the uid of the experiment created by the chaosblade tool
+func ParseCreateResponse(output []byte) (string, *spec.Response) {
+	response, resp := parseResponse(output)
+	if !resp.Success {
+		return "", resp
+	}
+	var uid string
+	if err := json.Unmarshal(response.Result, &uid); err != nil || uid == "" {
+		return "", spec.ResponseFailWithFlags(spec.ResultUnmarshalFailed, string(response.Result),
+			"the result of the create command must be the experiment uid")
+	}
+	return uid, spec.ReturnSuccess(uid)
+}
+
+// ParseDestroyResponse returns the result of the destroy command
+func ParseDestroyResponse(output []byte) *spec.Response {
+	_, resp := parseResponse(output)
+	return resp
+}
+
+// ParseStatusResponse returns the experiment status queried by uid, the first one is returned if the
+// result is a list
+func ParseStatusResponse(output []byte) (*BladeStatus, *spec.Response) {
+	statuses, resp := ParseStatusListResponse(output)
+	if !resp.Success {
+		return nil, resp
+	}
+	if len(statuses) == 0 {
+		return nil, spec.ResponseFailWithFlags(spec.ResultUnmarshalFailed, strings.TrimSpace(string(output)),
+			"the experiment status not found")
+	}
+	return &statuses[0], spec.ReturnSuccess(statuses[0])
+}
+
+// ParseStatusListResponse returns the experiment statuses queried by type, a single status is returned as
+// a list of one
+func ParseStatusListResponse(output []byte) ([]BladeStatus, *spec.Response) {
+	response, resp := parseResponse(output)
+	if !resp.Success {
+		return nil, resp
+	}
+	result := bytes.TrimSpace(response.Result)
+	statuses := make([]BladeStatus, 0)
+	if len(result) == 0 || bytes.Equal(result, []byte("null")) {
+		return statuses, spec.ReturnSuccess(statuses)
+	}
+	if bytes.HasPrefix(result, []byte("[")) {
+		if err := json.Unmarshal(result, &statuses); err != nil {
+			return nil, spec.ResponseFailWithFlags(spec.ResultUnmarshalFailed, string(result), err)
+		}
+		return statuses, spec.ReturnSuccess(statuses)
+	}
+	status := BladeStatus{}
+	if err := json.Unmarshal(result, &status); err != nil {
+		return nil, spec.ResponseFailWithFlags(spec.ResultUnmarshalFailed, string(result), err)
+	}
+	statuses = append(statuses, status)
+	return statuses, spec.ReturnSuccess(statuses)
+}
+
+// parseResponse converts the chaosblade response to the spec response, a failure response is returned
+// with the code and the error of the chaosblade tool
+func parseResponse(output []byte) (*BladeResponse, *spec.Response) {
+	response, err := ParseBladeResponse(output)
+	if err != nil {
+		return nil, spec.ResponseFailWithFlags(spec.ResultUnmarshalFailed, strings.TrimSpace(string(output)), err)
+	}
+	if !response.Success {
+		return response, &spec.Response{Code: response.Code, Success: false, Err: response.Err}
+	}
+	return response, spec.ReturnSuccess(nil)
+}
+
+// ParseExecResult parses the chaosblade response of the exec result by the parse function. The stdout is
+// parsed first and then the stderr, the exec error is returned if no response is found in both.
+func ParseExecResult(result *channel.ExecResult, execErr error, parse func(output []byte) *spec.Response) *spec.Response {
+	parseErr := errNoBladeResponse
+	if result != nil {
+		for i, output := range [][]byte{result.Stdout, result.Stderr} {
+			_, err := ParseBladeResponse(output)
+			if err == nil {
+				return parse(output)
+			}
+			if i == 0 {
+				parseErr = err
+			}
+		}
+	}
+	if execErr == nil && result != nil {
+		execErr = result.Err()
+	}
+	if execErr != nil {
+		return spec.ResponseFailWithFlags(spec.K8sExecFailed, "pods/exec", execErr)
+	}
+	output := ""
+	if result != nil {
+		output = strings.TrimSpace(string(result.Stdout) + string(result.Stderr))
+		if result.Truncated {
+			output = fmt.Sprintf("%s, the output is truncated", output)
+		}
+	}
+	return spec.ResponseFailWithFlags(spec.ResultUnmarshalFailed, output, parseErr)
+}
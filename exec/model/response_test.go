@@ -0,0 +1,94 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade-operator/channel"
+)
+
+func TestParseCreateResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{name: "success", output: `{"code":200,"success":true,"result":"7c1f7afc281482c8"}`, want: "7c1f7afc281482c8"},
+		{name: "noise", output: "time=\"2024\" level=warning msg=\"{cgroup}\"\n{\"code\":200,\"success\":true,\"result\":\"7c1f7afc281482c8\"}\n", want: "7c1f7afc281482c8"},
+		{name: "failure", output: `{"code":47000,"success":false,"error":"process not found"}`, wantErr: true},
+		{name: "partial", output: `{"code":200,"success":true,"res`, wantErr: true},
+		{name: "not json", output: "sh: blade: not found", wantErr: true},
+		{name: "illegal result", output: `{"code":200,"success":true,"result":{"Uid":"7c1f7afc281482c8"}}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uid, resp := ParseCreateResponse([]byte(tt.output))
+			if resp.Success == tt.wantErr {
+				t.Fatalf("ParseCreateResponse() response = %s, wantErr %t", resp.Print(), tt.wantErr)
+			}
+			if uid != tt.want {
+				t.Errorf("ParseCreateResponse() uid = %s, want %s", uid, tt.want)
+			}
+		})
+	}
+	_, resp := ParseCreateResponse([]byte(`{"code":47000,"success":false,"error":"process not found"}`))
+	if resp.Code != 47000 || resp.Err != "process not found" {
+		t.Errorf("expected the code and the error of the chaosblade response, got %s", resp.Print())
+	}
+}
+
+func TestParseStatusResponse(t *testing.T) {
+	for _, output := range []string{
+		`{"code":200,"success":true,"result":{"Uid":"7c1f7afc281482c8","Status":"Destroyed"}}`,
+		`{"code":200,"success":true,"result":[{"Uid":"7c1f7afc281482c8","Status":"Destroyed"}]}`,
+	} {
+		status, resp := ParseStatusResponse([]byte(output))
+		if !resp.Success || status.Uid != "7c1f7afc281482c8" || status.Status != "Destroyed" {
+			t.Errorf("unexpected status %+v of %s, %s", status, output, resp.Print())
+		}
+	}
+	if _, resp := ParseStatusResponse([]byte(`{"code":200,"success":true,"result":"Destroyed"}`)); resp.Success {
+		t.Errorf("expected the illegal result failed")
+	}
+}
+
+func TestParseExecResult(t *testing.T) {
+	parse := func(output []byte) *spec.Response {
+		return ParseDestroyResponse(output)
+	}
+	result := &channel.ExecResult{
+		ExitCode: 1,
+		Stdout:   []byte(`{"code":200,"success":true}`),
+		Stderr:   []byte("warning: ignored"),
+	}
+	if resp := ParseExecResult(result, nil, parse); !resp.Success {
+		t.Errorf("expected the response in the stdout is used, got %s", resp.Print())
+	}
+	if resp := ParseExecResult(&channel.ExecResult{}, errors.New("connection reset"), parse); resp.Success ||
+		resp.Code != spec.K8sExecFailed.Code {
+		t.Errorf("expected the exec error, got %s", resp.Print())
+	}
+	if resp := ParseExecResult(&channel.ExecResult{Stdout: []byte("ok")}, nil, parse); resp.Success ||
+		resp.Code != spec.ResultUnmarshalFailed.Code {
+		t.Errorf("expected the unmarshal error, got %s", resp.Print())
+	}
+}
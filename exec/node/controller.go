@@ -62,6 +62,19 @@ func (e *ExpController) Create(ctx context.Context, expSpec v1alpha1.ExperimentS
 	return e.Exec(ctx, expModel)
 }
 
+// SelectTargets returns the names of the nodes matched by the experiment
+func (e *ExpController) SelectTargets(ctx context.Context, expSpec v1alpha1.ExperimentSpec) ([]string, *spec.Response) {
+	nodes, resp := e.getMatchedNodeResources(ctx, *model.ExtractExpModelFromExperimentSpec(expSpec))
+	if !resp.Success {
+		return nil, resp
+	}
+	names := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		names = append(names, node.Name)
+	}
+	return names, spec.Success()
+}
+
 func (e *ExpController) Destroy(ctx context.Context, expSpec v1alpha1.ExperimentSpec, oldExpStatus v1alpha1.ExperimentStatus) *spec.Response {
 	logrus.WithField("experiment", model.GetExperimentIdFromContext(ctx)).Infoln("start to destroy")
 	expModel := model.ExtractExpModelFromExperimentSpec(expSpec)
@@ -392,7 +392,8 @@ blade create k8s node-script exit --exit-code 1 --exit-message this-is-error-mes
 
 func getResourceFlags() []spec.ExpFlagSpec {
 	coverageFlags := model.GetResourceCoverageFlags()
-	return append(coverageFlags, model.ResourceNamesFlag, model.ResourceLabelsFlag)
+	return append(coverageFlags, model.ResourceNamesFlag, model.ResourceLabelsFlag, model.SuccessPolicyFlag,
+		model.ExecConcurrencyFlag, model.ExecQPSFlag, model.ExecBurstFlag, model.ExecStaggerFlag)
 }
 
 func NewSelfExpModelCommandSpec() spec.ExpModelCommandSpec {
@@ -0,0 +1,236 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pod
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/chaosblade-io/chaosblade-operator/channel"
+	chaosfs "github.com/chaosblade-io/chaosblade-operator/pkg/hookfs"
+	webhook "github.com/chaosblade-io/chaosblade-operator/pkg/webhook/pod"
+)
+
+const (
+	// attachTimeout is the timeout of waiting for the ephemeral fuse container and its server
+	attachTimeout = time.Minute
+	attachPeriod  = time.Second
+)
+
+// getFuseContainer returns the running ephemeral fuse container of the pod
+func getFuseContainer(pod *v1.Pod) *v1.EphemeralContainer {
+	for _, containerStatus := range pod.Status.EphemeralContainerStatuses {
+		if !strings.HasPrefix(containerStatus.Name, webhook.SidecarName) || containerStatus.State.Running == nil {
+			continue
+		}
+		for i := range pod.Spec.EphemeralContainers {
+			if pod.Spec.EphemeralContainers[i].Name == containerStatus.Name {
+				return &pod.Spec.EphemeralContainers[i]
+			}
+		}
+	}
+	return nil
+}
+
+// hasFuseSidecar returns true if the fuse sidecar was injected by the webhook
+func hasFuseSidecar(pod *v1.Pod) bool {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == webhook.SidecarName {
+			return true
+		}
+	}
+	return false
+}
+
+// attachFuseContainer attaches an ephemeral container running chaos_fuse to the pod, which mounts over
+// the mountPath in the mount namespace of the target container, and waits until the container is running
+func attachFuseContainer(ctx context.Context, client *channel.Client, pod *v1.Pod,
+	containerName, mountPath, experimentId string) (*v1.Pod, error) {
+	if fuseContainer := getFuseContainer(pod); fuseContainer != nil {
+		if !containsString(fuseContainer.Args, fmt.Sprintf("--mountpoint=%s", mountPath)) {
+			return nil, fmt.Errorf("the fuse container %s has been attached to another mount path", fuseContainer.Name)
+		}
+		return pod, nil
+	}
+	if containerName == "" {
+		containerName = pod.Spec.Containers[0].Name
+	}
+	found := false
+	for _, container := range pod.Spec.Containers {
+		if container.Name == containerName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("container %s not found", containerName)
+	}
+	if webhook.FuseAuthEnable {
+		if err := webhook.EnsureFuseAuthSecret(ctx, client.Interface, pod.Namespace); err != nil {
+			return nil, fmt.Errorf("ensure fuse auth secret error, %v", err)
+		}
+	}
+	fuseContainer := newFuseContainer(containerName, mountPath, experimentId)
+	pod = pod.DeepCopy()
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, fuseContainer)
+	if _, err := client.CoreV1().Pods(pod.Namespace).UpdateEphemeralContainers(ctx, pod.Name, pod,
+		metav1.UpdateOptions{}); err != nil {
+		return nil, fmt.Errorf("attach ephemeral container error, %v", err)
+	}
+	return waitFuseContainer(ctx, client, pod, fuseContainer.Name)
+}
+
+func newFuseContainer(containerName, mountPath, experimentId string) v1.EphemeralContainer {
+	name := webhook.SidecarName
+	if experimentId != "" {
+		if len(experimentId) > 8 {
+			experimentId = experimentId[:8]
+		}
+		name = fmt.Sprintf("%s-%s", webhook.SidecarName, strings.ToLower(experimentId))
+	}
+	// the ephemeral container mounts in the mount namespace of the target, so it needs no mount propagation
+	// and works with the template which is not privileged. The resources of the template are not applied,
+	// because they are not allowed for the ephemeral containers.
+	template := webhook.GetSidecarTemplate()
+	image := webhook.GetSidecarImage()
+	if template.Image != "" {
+		image = template.Image
+	}
+	fuseContainer := v1.EphemeralContainer{
+		EphemeralContainerCommon: v1.EphemeralContainerCommon{
+			Name:            name,
+			Image:           image,
+			ImagePullPolicy: template.ImagePullPolicy,
+			Command:         append([]string{}, template.Command...),
+			Args: []string{
+				fmt.Sprintf("--address=:%d", webhook.FuseServerPort),
+				fmt.Sprintf("--mountpoint=%s", mountPath),
+				// the ephemeral container shares the process namespace of the target container
+				"--target-pid=1",
+			},
+			SecurityContext: template.SecurityContext.DeepCopy(),
+		},
+		TargetContainerName: containerName,
+	}
+	if webhook.FuseAuthEnable {
+		fuseContainer.Args = append(fuseContainer.Args, "--auth-from-env")
+		for env, key := range map[string]string{
+			chaosfs.AuthTokenEnv: chaosfs.AuthTokenKey,
+			chaosfs.AuthCertEnv:  chaosfs.AuthCertKey,
+			chaosfs.AuthKeyEnv:   chaosfs.AuthKeyKey,
+		} {
+			fuseContainer.Env = append(fuseContainer.Env, v1.EnvVar{
+				Name: env,
+				ValueFrom: &v1.EnvVarSource{
+					SecretKeyRef: &v1.SecretKeySelector{
+						LocalObjectReference: v1.LocalObjectReference{Name: webhook.FuseAuthSecretName},
+						Key:                  key,
+					},
+				},
+			})
+		}
+	}
+	return fuseContainer
+}
+
+func waitFuseContainer(ctx context.Context, client *channel.Client, pod *v1.Pod, name string) (*v1.Pod, error) {
+	ctx, cancel := context.WithTimeout(ctx, attachTimeout)
+	defer cancel()
+	ticker := time.NewTicker(attachPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait for ephemeral container %s running timeout", name)
+		case <-ticker.C:
+			latest, err := client.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+			if err != nil {
+				continue
+			}
+			for _, containerStatus := range latest.Status.EphemeralContainerStatuses {
+				if containerStatus.Name != name {
+					continue
+				}
+				if terminated := containerStatus.State.Terminated; terminated != nil {
+					return nil, fmt.Errorf("ephemeral container %s terminated, reason: %s, message: %s",
+						name, terminated.Reason, terminated.Message)
+				}
+				if containerStatus.State.Running != nil {
+					return latest, nil
+				}
+			}
+		}
+	}
+}
+
+// waitFuseServer waits until the fuse server in the ephemeral container is serving
+func waitFuseServer(ctx context.Context, chaosfsClient *chaosfs.ChaosBladeHookClient) error {
+	ctx, cancel := context.WithTimeout(ctx, attachTimeout)
+	defer cancel()
+	var err error
+	for {
+		if _, err = chaosfsClient.Status(ctx); err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("wait for fuse server timeout, %v", err)
+		case <-time.After(attachPeriod):
+		}
+	}
+}
+
+// getFuseServerPort returns the port of the fuse server in the sidecar or the ephemeral container
+func getFuseServerPort(pod *v1.Pod) (int32, error) {
+	if hasFuseSidecar(pod) {
+		return getContainerPort(webhook.FuseServerPortName, pod)
+	}
+	fuseContainer := getFuseContainer(pod)
+	if fuseContainer == nil {
+		return 0, fmt.Errorf("can not found fuse sidecar or ephemeral container")
+	}
+	for _, arg := range fuseContainer.Args {
+		var port int32
+		if _, err := fmt.Sscanf(arg, "--address=:%d", &port); err == nil {
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("can not found fuse server port of ephemeral container %s", fuseContainer.Name)
+}
+
+// hasFuseAuth returns true if the fuse server in the sidecar or the ephemeral container requires the token
+func hasFuseAuth(pod *v1.Pod) bool {
+	if hasFuseSidecar(pod) {
+		return webhook.HasFuseAuth(pod)
+	}
+	fuseContainer := getFuseContainer(pod)
+	return fuseContainer != nil && containsString(fuseContainer.Args, "--auth-from-env")
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
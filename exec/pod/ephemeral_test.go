@@ -0,0 +1,202 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pod
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/chaosblade-io/chaosblade-operator/channel"
+	webhook "github.com/chaosblade-io/chaosblade-operator/pkg/webhook/pod"
+)
+
+func newEphemeralPod(name string, args []string, running bool) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default"},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: "app"}},
+		},
+	}
+	if name == "" {
+		return pod
+	}
+	pod.Spec.EphemeralContainers = []v1.EphemeralContainer{{
+		EphemeralContainerCommon: v1.EphemeralContainerCommon{Name: name, Args: args},
+		TargetContainerName:      "app",
+	}}
+	state := v1.ContainerState{Waiting: &v1.ContainerStateWaiting{}}
+	if running {
+		state = v1.ContainerState{Running: &v1.ContainerStateRunning{}}
+	}
+	pod.Status.EphemeralContainerStatuses = []v1.ContainerStatus{{Name: name, State: state}}
+	return pod
+}
+
+func newSidecarPod(auth bool) *v1.Pod {
+	sidecar := v1.Container{
+		Name:  webhook.SidecarName,
+		Ports: []v1.ContainerPort{{Name: webhook.FuseServerPortName, ContainerPort: 65534}},
+	}
+	if auth {
+		sidecar.VolumeMounts = []v1.VolumeMount{{Name: webhook.FuseAuthVolumeName}}
+	}
+	return &v1.Pod{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{sidecar, {Name: "app"}},
+		},
+	}
+}
+
+func Test_getFuseContainer(t *testing.T) {
+	tests := []struct {
+		name string
+		pod  *v1.Pod
+		want string
+	}{
+		{name: "no ephemeral container", pod: newEphemeralPod("", nil, false), want: ""},
+		{name: "fuse container running", pod: newEphemeralPod(webhook.SidecarName+"-abc", nil, true), want: webhook.SidecarName + "-abc"},
+		{name: "fuse container waiting", pod: newEphemeralPod(webhook.SidecarName+"-abc", nil, false), want: ""},
+		{name: "other container running", pod: newEphemeralPod("debugger", nil, true), want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if container := getFuseContainer(tt.pod); container != nil {
+				got = container.Name
+			}
+			if got != tt.want {
+				t.Errorf("getFuseContainer() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getFuseServerPort(t *testing.T) {
+	tests := []struct {
+		name    string
+		pod     *v1.Pod
+		want    int32
+		wantErr bool
+	}{
+		{name: "sidecar", pod: newSidecarPod(false), want: 65534},
+		{name: "ephemeral container", pod: newEphemeralPod(webhook.SidecarName, []string{"--mountpoint=/data", "--address=:65533"}, true), want: 65533},
+		{name: "ephemeral container without address", pod: newEphemeralPod(webhook.SidecarName, []string{"--mountpoint=/data"}, true), wantErr: true},
+		{name: "no fuse server", pod: newEphemeralPod("", nil, false), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getFuseServerPort(tt.pod)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getFuseServerPort() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("getFuseServerPort() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_hasFuseAuth(t *testing.T) {
+	tests := []struct {
+		name string
+		pod  *v1.Pod
+		want bool
+	}{
+		{name: "sidecar with auth", pod: newSidecarPod(true), want: true},
+		{name: "sidecar without auth", pod: newSidecarPod(false), want: false},
+		{name: "ephemeral container with auth", pod: newEphemeralPod(webhook.SidecarName, []string{"--auth-from-env"}, true), want: true},
+		{name: "ephemeral container without auth", pod: newEphemeralPod(webhook.SidecarName, nil, true), want: false},
+		{name: "no fuse server", pod: newEphemeralPod("", nil, false), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasFuseAuth(tt.pod); got != tt.want {
+				t.Errorf("hasFuseAuth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newFuseContainer(t *testing.T) {
+	defer func(enable bool) { webhook.FuseAuthEnable = enable }(webhook.FuseAuthEnable)
+	tests := []struct {
+		name         string
+		experimentId string
+		authEnable   bool
+		wantName     string
+		wantEnvs     int
+	}{
+		{name: "without experiment", wantName: webhook.SidecarName},
+		{name: "short experiment id", experimentId: "ABC", wantName: webhook.SidecarName + "-abc"},
+		{name: "long experiment id", experimentId: "0123456789ABCDEF", wantName: webhook.SidecarName + "-01234567"},
+		{name: "auth enabled", authEnable: true, wantName: webhook.SidecarName, wantEnvs: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook.FuseAuthEnable = tt.authEnable
+			container := newFuseContainer("app", "/data", tt.experimentId)
+			if container.Name != tt.wantName {
+				t.Errorf("name = %q, want %q", container.Name, tt.wantName)
+			}
+			if container.TargetContainerName != "app" {
+				t.Errorf("target container = %q, want app", container.TargetContainerName)
+			}
+			if !containsString(container.Args, "--mountpoint=/data") {
+				t.Errorf("args %v have no mountpoint", container.Args)
+			}
+			if container.SecurityContext == nil || container.SecurityContext.Privileged == nil || !*container.SecurityContext.Privileged {
+				t.Errorf("the fuse container with the default template is not privileged")
+			}
+			if len(container.Env) != tt.wantEnvs || containsString(container.Args, "--auth-from-env") != tt.authEnable {
+				t.Errorf("unexpected auth envs %v and args %v", container.Env, container.Args)
+			}
+		})
+	}
+}
+
+func Test_attachFuseContainer(t *testing.T) {
+	client := &channel.Client{Interface: fake.NewSimpleClientset()}
+	tests := []struct {
+		name          string
+		pod           *v1.Pod
+		containerName string
+		wantErr       string
+	}{
+		{name: "attached to the mount path", pod: newEphemeralPod(webhook.SidecarName, []string{"--mountpoint=/data"}, true)},
+		{name: "attached to another mount path", pod: newEphemeralPod(webhook.SidecarName, []string{"--mountpoint=/other"}, true), wantErr: "another mount path"},
+		{name: "container not found", pod: newEphemeralPod("", nil, false), containerName: "not-exists", wantErr: "not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := attachFuseContainer(context.Background(), client, tt.pod, tt.containerName, "/data", "")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("attachFuseContainer() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.pod {
+				t.Errorf("attachFuseContainer() = %v, %v, want the attached pod", got, err)
+			}
+		})
+	}
+}
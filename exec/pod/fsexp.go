@@ -19,19 +19,25 @@ package pod
 import (
 	"context"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/chaosblade-io/chaosblade-spec-go/spec"
 	"github.com/chaosblade-io/chaosblade-spec-go/util"
 	"github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/chaosblade-io/chaosblade-operator/channel"
 	"github.com/chaosblade-io/chaosblade-operator/exec/model"
 	"github.com/chaosblade-io/chaosblade-operator/pkg/apis/chaosblade/v1alpha1"
 	chaosfs "github.com/chaosblade-io/chaosblade-operator/pkg/hookfs"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/runtime/chaosblade"
 	webhook "github.com/chaosblade-io/chaosblade-operator/pkg/webhook/pod"
 )
 
@@ -70,10 +76,45 @@ func NewPodIOActionSpec(client *channel.Client) spec.ExpActionCommandSpec {
 					Name: "errno",
 					Desc: "I/O error code",
 				},
+				&spec.ExpFlag{
+					Name: "pid",
+					Desc: "Only inject the fault for the calls of the process ids, multiple values are separated by commas. The pod must share the process namespace",
+				},
+				&spec.ExpFlag{
+					Name: "process",
+					Desc: "Only inject the fault for the calls of the process names, multiple values are separated by commas. The pod must share the process namespace",
+				},
+				&spec.ExpFlag{
+					Name: "uid",
+					Desc: "Only inject the fault for the calls of the user ids, multiple values are separated by commas",
+				},
+				&spec.ExpFlag{
+					Name:   "ephemeral",
+					Desc:   "Attach an ephemeral container running chaos_fuse to the running pod if the fuse sidecar was not injected, the container exits after the experiment is destroyed",
+					NoArgs: true,
+				},
+				&spec.ExpFlag{
+					Name: "mount-path",
+					Desc: "The directory in the container mounted over by the ephemeral container, the path flag is used if empty. Only for the ephemeral flag",
+				},
+				&spec.ExpFlag{
+					Name: "container-name",
+					Desc: "The container whose mount-path is mounted over by the ephemeral container, the first container is used if empty. Only for the ephemeral flag",
+				},
+				&spec.ExpFlag{
+					Name: "ttl",
+					Desc: "The fault is removed by the sidecar itself if the lease is not renewed within the ttl, such as 60s or 60(seconds). The operator renews the lease while the experiment exists",
+				},
 			},
 			ActionExecutor: &PodIOActionExecutor{client: client},
 			ActionExample: `# Two types of exceptions were injected for the READ operation, with an exception rate of 60 percent
-blade create k8s pod-pod IO --method read --delay 1000 --path /home --percent 60 --errno 28 --labels "app=test" --namespace default`,
+blade create k8s pod-pod IO --method read --delay 1000 --path /home --percent 60 --errno 28 --labels "app=test" --namespace default
+
+# Only the mysqld process is affected, the other processes writing to the same volume are not
+blade create k8s pod-pod IO --method write --errno 5 --path /data --process mysqld --labels "app=mysql" --namespace default
+
+# Inject the read delay into a running pod without the fuse sidecar by an ephemeral container
+blade create k8s pod-pod IO --method read --delay 1000 --path /data --ephemeral --container-name nginx --names nginx-app --namespace default`,
 			ActionCategories: []string{model.CategorySystemContainer},
 		},
 	}
@@ -131,7 +172,7 @@ func (d *PodIOActionExecutor) create(ctx context.Context, expModel *spec.ExpMode
 			Identifier: c.GetIdentifier(),
 		}
 		pod := &v1.Pod{}
-		err := d.client.Get(context.TODO(), client.ObjectKey{Namespace: c.Namespace, Name: c.PodName}, pod)
+		err := d.client.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: c.PodName}, pod)
 		if err != nil {
 			logrusField.Errorf("get pod %s err, %v", c.PodName, err)
 			statuses = append(statuses, status.CreateFailResourceStatus(
@@ -143,61 +184,36 @@ func (d *PodIOActionExecutor) create(ctx context.Context, expModel *spec.ExpMode
 			statuses = append(statuses, status.CreateFailResourceStatus(spec.PodNotReady.Msg, spec.PodNotReady.Code))
 			continue
 		}
-		methods, ok := expModel.ActionFlags["method"]
-		if !ok && len(methods) != 0 {
-			logrusField.Error("method cannot be empty")
-			statuses = append(statuses, status.CreateFailResourceStatus(
-				spec.ParameterLess.Sprintf("method"), spec.ParameterLess.Code))
+		request, resp := newInjectMessage(expModel.ActionFlags)
+		if !resp.Success {
+			logrusField.Errorf("illegal flags, %s", resp.Err)
+			statuses = append(statuses, status.CreateFailResourceStatus(resp.Err, resp.Code))
 			continue
 		}
 
-		var delay, percent, errno int
-		delayStr, ok := expModel.ActionFlags["delay"]
-		if ok && len(delayStr) != 0 {
-			delay, err = strconv.Atoi(delayStr)
-			if err != nil {
-				logrusField.Error("delay must be integer")
-				statuses = append(statuses, status.CreateFailResourceStatus(
-					spec.ParameterIllegal.Sprintf("delay", delayStr, err), spec.ParameterIllegal.Code))
-				continue
+		attached := false
+		if expModel.ActionFlags["ephemeral"] == "true" && !hasFuseSidecar(pod) {
+			mountPath := expModel.ActionFlags["mount-path"]
+			if mountPath == "" {
+				mountPath = request.Path
 			}
-		}
-		percentStr, ok := expModel.ActionFlags["percent"]
-		if ok && len(percentStr) != 0 {
-			if percent, err = strconv.Atoi(percentStr); err != nil {
-				logrusField.Error("percent must be integer")
+			if mountPath == "" {
+				logrusField.Error("mount-path cannot be empty")
 				statuses = append(statuses, status.CreateFailResourceStatus(
-					spec.ParameterIllegal.Sprintf("percent", percentStr, err), spec.ParameterIllegal.Code))
+					spec.ParameterLess.Sprintf("mount-path"), spec.ParameterLess.Code))
 				continue
 			}
-		}
-
-		errnoStr, ok := expModel.ActionFlags["errno"]
-		if ok && len(errnoStr) != 0 {
-			if errno, err = strconv.Atoi(errnoStr); err != nil {
-				logrusField.Error("errno must be integer")
+			pod, err = attachFuseContainer(ctx, d.client, pod, expModel.ActionFlags["container-name"], mountPath, experimentId)
+			if err != nil {
+				logrusField.Errorf("attach ephemeral fuse container to pod %s failed, %v", c.PodName, err)
 				statuses = append(statuses, status.CreateFailResourceStatus(
-					spec.ParameterIllegal.Sprintf("errno", errnoStr, err), spec.ParameterIllegal.Code))
+					spec.ChaosfsClientFailed.Sprintf(c.PodName, err), spec.ChaosfsClientFailed.Code))
 				continue
 			}
+			attached = true
 		}
 
-		random := false
-		randomStr, ok := expModel.ActionFlags["random"]
-		if ok && randomStr == "true" {
-			random = true
-		}
-
-		request := &chaosfs.InjectMessage{
-			Methods: strings.Split(methods, ","),
-			Path:    expModel.ActionFlags["path"],
-			Delay:   uint32(delay),
-			Percent: uint32(percent),
-			Random:  random,
-			Errno:   uint32(errno),
-		}
-
-		chaosfsClient, err := getChaosfsClient(pod)
+		chaosfsClient, err := getChaosfsClient(ctx, d.client, pod)
 		if err != nil {
 			logrusField.WithField("pod", c.PodName).WithField("request", request).
 				Errorf("init chaosfs client failed: %v", err)
@@ -205,6 +221,14 @@ func (d *PodIOActionExecutor) create(ctx context.Context, expModel *spec.ExpMode
 				spec.ChaosfsClientFailed.Sprintf(pod.Name, err), spec.ChaosfsClientFailed.Code))
 			continue
 		}
+		if attached {
+			if err := waitFuseServer(ctx, chaosfsClient); err != nil {
+				logrusField.Errorf("the fuse server in pod %s is not serving, %v", c.PodName, err)
+				statuses = append(statuses, status.CreateFailResourceStatus(
+					spec.ChaosfsClientFailed.Sprintf(pod.Name, err), spec.ChaosfsClientFailed.Code))
+				continue
+			}
+		}
 		err = chaosfsClient.InjectFault(ctx, request)
 		if err != nil {
 			logrusField.Errorf("inject io exception in pod %s failed, request %v, err: %v", c.PodName, request, err)
@@ -224,6 +248,174 @@ func (d *PodIOActionExecutor) create(ctx context.Context, expModel *spec.ExpMode
 	return spec.ReturnResultIgnoreCode(experimentStatus)
 }
 
+// newInjectMessage returns the request of the fault rule by the flags
+func newInjectMessage(flags map[string]string) (*chaosfs.InjectMessage, *spec.Response) {
+	methods, ok := flags["method"]
+	if !ok && len(methods) != 0 {
+		return nil, spec.ResponseFailWithFlags(spec.ParameterLess, "method")
+	}
+	var delay, percent, errno int
+	var err error
+	if delayStr := flags["delay"]; len(delayStr) != 0 {
+		if delay, err = strconv.Atoi(delayStr); err != nil {
+			return nil, spec.ResponseFailWithFlags(spec.ParameterIllegal, "delay", delayStr, err)
+		}
+	}
+	if percentStr := flags["percent"]; len(percentStr) != 0 {
+		if percent, err = strconv.Atoi(percentStr); err != nil {
+			return nil, spec.ResponseFailWithFlags(spec.ParameterIllegal, "percent", percentStr, err)
+		}
+	}
+	if errnoStr := flags["errno"]; len(errnoStr) != 0 {
+		if errno, err = strconv.Atoi(errnoStr); err != nil {
+			return nil, spec.ResponseFailWithFlags(spec.ParameterIllegal, "errno", errnoStr, err)
+		}
+	}
+	pids, err := parseUint32List(flags["pid"])
+	if err != nil {
+		return nil, spec.ResponseFailWithFlags(spec.ParameterIllegal, "pid", flags["pid"], err)
+	}
+	uids, err := parseUint32List(flags["uid"])
+	if err != nil {
+		return nil, spec.ResponseFailWithFlags(spec.ParameterIllegal, "uid", flags["uid"], err)
+	}
+	ttl, err := getIOFaultTTL(flags["ttl"])
+	if err != nil {
+		return nil, spec.ResponseFailWithFlags(spec.ParameterIllegal, "ttl", flags["ttl"], err)
+	}
+	var processNames []string
+	if processStr := flags["process"]; processStr != "" {
+		processNames = strings.Split(processStr, ",")
+	}
+	return &chaosfs.InjectMessage{
+		Methods: strings.Split(methods, ","),
+		Path:    flags["path"],
+		Delay:   uint32(delay),
+		Percent: uint32(percent),
+		Random:  flags["random"] == "true",
+		Errno:   uint32(errno),
+
+		Pids:         pids,
+		ProcessNames: processNames,
+		Uids:         uids,
+		TTL:          ttl,
+	}, spec.Success()
+}
+
+// updatableIOFlags are the flags of the rule replaced in place, the rules are stored by the methods
+var updatableIOFlags = map[string]bool{
+	"delay":   true,
+	"percent": true,
+	"errno":   true,
+	"random":  true,
+	"path":    true,
+	"pid":     true,
+	"process": true,
+	"uid":     true,
+}
+
+func (d *PodIOActionExecutor) Updatable(changedFlags []string) bool {
+	for _, flag := range changedFlags {
+		if !updatableIOFlags[flag] {
+			return false
+		}
+	}
+	return true
+}
+
+// Update replaces the fault rules in the fuse servers of the pods, the fault is not recovered during the update.
+// The previous rules are applied again to the targets failed to update
+func (d *PodIOActionExecutor) Update(ctx context.Context, expModel *spec.ExpModel) *spec.Response {
+	containerMatchedList, err := model.GetContainerObjectMetaListFromContext(ctx)
+	experimentId := model.GetExperimentIdFromContext(ctx)
+	if err != nil {
+		util.Errorf(experimentId, util.GetRunFuncName(), err.Error())
+		return spec.ResponseFailWithResult(spec.ContainerInContextNotFound,
+			v1alpha1.CreateFailExperimentStatus(spec.ContainerInContextNotFound.Msg, []v1alpha1.ResourceStatus{}))
+	}
+	request, resp := newInjectMessage(expModel.ActionFlags)
+	if !resp.Success {
+		return spec.ResponseFailWithResult(spec.ParameterIllegal,
+			v1alpha1.CreateFailExperimentStatus(resp.Err, []v1alpha1.ResourceStatus{}), resp.Err)
+	}
+	logrusField := logrus.WithField("experiment", experimentId)
+	statuses := make([]v1alpha1.ResourceStatus, 0)
+	success := false
+	for _, c := range containerMatchedList {
+		status := v1alpha1.ResourceStatus{
+			Kind:       v1alpha1.PodKind,
+			Identifier: c.GetIdentifier(),
+		}
+		pod := &v1.Pod{}
+		if err := d.client.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: c.PodName}, pod); err != nil {
+			logrusField.Errorf("get pod %s err, %v", c.PodName, err)
+			statuses = append(statuses, status.CreateFailResourceStatus(
+				spec.K8sExecFailed.Sprintf("get", err), spec.K8sExecFailed.Code))
+			continue
+		}
+		chaosfsClient, err := getChaosfsClient(ctx, d.client, pod)
+		if err != nil {
+			logrusField.Errorf("init chaosfs client failed in pod %v, err: %v", pod.Name, err)
+			statuses = append(statuses, status.CreateFailResourceStatus(
+				spec.ChaosfsClientFailed.Sprintf(pod.Name, err), spec.ChaosfsClientFailed.Code))
+			continue
+		}
+		status, updated := updateIORules(ctx, logrusField.WithField("pod", c.PodName), chaosfsClient, request, status)
+		statuses = append(statuses, status)
+		success = success || updated
+	}
+	if success {
+		return spec.ReturnResultIgnoreCode(v1alpha1.CreateSuccessExperimentStatus(statuses))
+	}
+	return spec.ReturnResultIgnoreCode(v1alpha1.CreateFailExperimentStatus("see resStatuses for details", statuses))
+}
+
+// ioRuleClient applies the fault rules in the fuse server
+type ioRuleClient interface {
+	InjectFault(ctx context.Context, injectMsg *chaosfs.InjectMessage) error
+	Revoke(ctx context.Context) error
+	Status(ctx context.Context) ([]chaosfs.RuleStatus, error)
+}
+
+// updateIORules replaces the rules by the request and returns true if updated. The previous rules are applied
+// again if the update fails, the target keeps successful with the error since the previous fault is destroyed
+// with the experiment. The target is destroyed if the previous rules cannot be applied.
+func updateIORules(ctx context.Context, logrusField *logrus.Entry, chaosfsClient ioRuleClient,
+	request *chaosfs.InjectMessage, status v1alpha1.ResourceStatus,
+) (v1alpha1.ResourceStatus, bool) {
+	previous, err := chaosfsClient.Status(ctx)
+	if err != nil {
+		logrusField.Errorf("get the previous io exception failed, err: %v", err)
+		status = status.CreateSuccessResourceStatus()
+		status.Error = fmt.Sprintf("update io exception failed, the previous fault is kept, get the previous rules error, %v", err)
+		return status, false
+	}
+	err = chaosfsClient.InjectFault(ctx, request)
+	if err == nil {
+		return status.CreateSuccessResourceStatus(), true
+	}
+	logrusField.Errorf("update io exception failed, request %v, err: %v", request, err)
+	// the rules of the request may be partially applied
+	restoreErr := chaosfsClient.Revoke(ctx)
+	for i := 0; restoreErr == nil && i < len(previous); i++ {
+		restoreErr = chaosfsClient.InjectFault(ctx, &previous[i].InjectMessage)
+	}
+	if restoreErr == nil {
+		status = status.CreateSuccessResourceStatus()
+		status.Error = fmt.Sprintf("update io exception failed, the previous fault is kept, %v", err)
+		return status, false
+	}
+	logrusField.Errorf("restore the previous io exception failed, err: %v", restoreErr)
+	if revokeErr := chaosfsClient.Revoke(ctx); revokeErr != nil {
+		return status.CreateFailResourceStatus(fmt.Sprintf("update io exception failed, %v, restore the previous fault error, %v",
+			err, restoreErr), spec.ChaosfsInjectFailed.Code), false
+	}
+	status = status.CreateFailResourceStatus(fmt.Sprintf("update io exception failed, %v, the previous fault is removed since restoring it error, %v",
+		err, restoreErr), spec.ChaosfsInjectFailed.Code)
+	status.State = v1alpha1.DestroyedState
+	return status, false
+}
+
 func (d *PodIOActionExecutor) destroy(ctx context.Context, expModel *spec.ExpModel) *spec.Response {
 	containerMatchedList, err := model.GetContainerObjectMetaListFromContext(ctx)
 	experimentId := model.GetExperimentIdFromContext(ctx)
@@ -241,7 +433,7 @@ func (d *PodIOActionExecutor) destroy(ctx context.Context, expModel *spec.ExpMod
 			Identifier: c.GetIdentifier(),
 		}
 		pod := &v1.Pod{}
-		err := d.client.Get(context.TODO(), client.ObjectKey{Namespace: c.Namespace, Name: c.PodName}, pod)
+		err := d.client.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: c.PodName}, pod)
 		if err != nil {
 			logrusField.Errorf("get pod %s err, %v", c.PodName, err)
 			continue
@@ -251,13 +443,19 @@ func (d *PodIOActionExecutor) destroy(ctx context.Context, expModel *spec.ExpMod
 			continue
 		}
 
-		chaosfsClient, err := getChaosfsClient(pod)
+		chaosfsClient, err := getChaosfsClient(ctx, d.client, pod)
 		if err != nil {
 			logrusField.Errorf("init chaosfs client failed in pod %v, err: %v", pod.Name, err)
 			statuses = append(statuses, status.CreateFailResourceStatus(
 				spec.ChaosfsClientFailed.Sprintf(pod.Name, err), spec.ChaosfsClientFailed.Code))
 			continue
 		}
+		ruleStatuses, err := chaosfsClient.Status(ctx)
+		if err != nil {
+			logrusField.Warningf("get io exception statistics failed in pod %v, err: %v", c.PodName, err)
+		} else {
+			status.IOFaultStatistics = getIOFaultStatistics(ruleStatuses)
+		}
 		err = chaosfsClient.Revoke(ctx)
 		if err != nil {
 			logrusField.Errorf("recover io exception failed in pod  %v, err: %v", c.PodName, err)
@@ -265,6 +463,15 @@ func (d *PodIOActionExecutor) destroy(ctx context.Context, expModel *spec.ExpMod
 				spec.ChaosfsRecoverFailed.Sprintf(pod.Name, err), spec.ChaosfsRecoverFailed.Code))
 			continue
 		}
+		if !hasFuseSidecar(pod) {
+			// the ephemeral container can not be removed from the pod, it unmounts and exits
+			if err := chaosfsClient.Shutdown(ctx); err != nil {
+				logrusField.Warningf("shutdown the ephemeral fuse container in pod %v failed, err: %v", c.PodName, err)
+			}
+		}
+		status.State = v1alpha1.DestroyedState
+		status.Success = true
+		statuses = append(statuses, status)
 	}
 	experimentStatus.ResStatuses = statuses
 	return spec.ReturnResultIgnoreCode(experimentStatus)
@@ -283,13 +490,171 @@ func isPodReady(pod *v1.Pod) bool {
 	return false
 }
 
-func getChaosfsClient(pod *v1.Pod) (*chaosfs.ChaosBladeHookClient, error) {
-	port, err := getContainerPort(webhook.FuseServerPortName, pod)
+// getIOFaultStatistics sums the hit counters of all rules in the chaos_fuse sidecar
+func getIOFaultStatistics(ruleStatuses []chaosfs.RuleStatus) *v1alpha1.IOFaultStatistics {
+	statistics := &v1alpha1.IOFaultStatistics{}
+	for _, ruleStatus := range ruleStatuses {
+		statistics.Matched += int64(ruleStatus.Matched)
+		statistics.Injected += int64(ruleStatus.Injected)
+		if ruleStatus.LastHit == nil {
+			continue
+		}
+		if statistics.LastHitTime == nil || statistics.LastHitTime.Time.Before(*ruleStatus.LastHit) {
+			lastHitTime := metav1.NewTime(*ruleStatus.LastHit)
+			statistics.LastHitTime = &lastHitTime
+		}
+	}
+	return statistics
+}
+
+// getIOFaultTTL returns the ttl seconds of the fault, uses the operator default if the value is empty
+func getIOFaultTTL(value string) (uint32, error) {
+	ttl := chaosblade.IOFaultTTL
+	if value != "" {
+		if seconds, err := strconv.ParseUint(value, 10, 32); err == nil {
+			ttl = time.Duration(seconds) * time.Second
+		} else if ttl, err = time.ParseDuration(value); err != nil {
+			return 0, err
+		}
+	}
+	if ttl <= 0 {
+		return 0, nil
+	}
+	if ttl <= chaosblade.IOFaultLeaseInterval {
+		return 0, fmt.Errorf("must be greater than the lease interval %s", chaosblade.IOFaultLeaseInterval)
+	}
+	return uint32(math.Ceil(ttl.Seconds())), nil
+}
+
+// ioFaultLeaseTarget is the pod of the IO fault with ttl, whose lease is renewed by the operator
+type ioFaultLeaseTarget struct {
+	bladeName string
+	meta      model.ContainerObjectMeta
+}
+
+// RenewIOFaultLeases renews the leases of the IO faults with ttl injected by the blades concurrently,
+// the faults are removed by the sidecar if the operator stops renewing
+func RenewIOFaultLeases(ctx context.Context, client *channel.Client, blades []*v1alpha1.ChaosBlade) {
+	targets := ioFaultLeaseTargets(blades)
+	secrets := newFuseAuthSecrets(client)
+	model.ParallelizeExec(len(targets), func(i int) {
+		target := targets[i]
+		logrusField := logrus.WithFields(logrus.Fields{
+			"blade":     target.bladeName,
+			"namespace": target.meta.Namespace,
+			"pod":       target.meta.PodName,
+		})
+		pod := &v1.Pod{}
+		if err := client.Get(ctx, types.NamespacedName{Namespace: target.meta.Namespace, Name: target.meta.PodName}, pod); err != nil {
+			logrusField.Warningf("get pod failed when renewing the io fault lease, %v", err)
+			return
+		}
+		chaosfsClient, err := secrets.chaosfsClient(ctx, pod)
+		if err != nil {
+			logrusField.Warningf("init chaosfs client failed when renewing the io fault lease, %v", err)
+			return
+		}
+		if err := chaosfsClient.Lease(ctx); err != nil {
+			logrusField.Warningf("renew the io fault lease failed, %v", err)
+		}
+	})
+}
+
+// ioFaultLeaseTargets returns the successful targets of the IO experiments with ttl, the faults without ttl
+// never expire and need no lease
+func ioFaultLeaseTargets(blades []*v1alpha1.ChaosBlade) []ioFaultLeaseTarget {
+	targets := make([]ioFaultLeaseTarget, 0)
+	for _, blade := range blades {
+		for _, expStatus := range blade.Status.ExpStatuses {
+			if !strings.EqualFold(expStatus.Scope, "pod") || !strings.EqualFold(expStatus.Target, "pod") ||
+				!strings.EqualFold(expStatus.Action, "IO") {
+				continue
+			}
+			ttlValue := ""
+			if expStatus.AppliedSpec != nil {
+				ttlValue = model.ExtractExpModelFromExperimentSpec(*expStatus.AppliedSpec).ActionFlags["ttl"]
+			}
+			if ttl, err := getIOFaultTTL(ttlValue); err != nil || ttl == 0 {
+				continue
+			}
+			for _, resStatus := range expStatus.ResStatuses {
+				if !resStatus.Success || resStatus.State != v1alpha1.SuccessState {
+					continue
+				}
+				targets = append(targets, ioFaultLeaseTarget{
+					bladeName: blade.Name,
+					meta:      model.ParseIdentifier(resStatus.Identifier),
+				})
+			}
+		}
+	}
+	return targets
+}
+
+// parseUint32List parses the comma separated integers
+func parseUint32List(value string) ([]uint32, error) {
+	if value == "" {
+		return nil, nil
+	}
+	values := make([]uint32, 0)
+	for _, v := range strings.Split(value, ",") {
+		i, err := strconv.ParseUint(strings.TrimSpace(v), 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, uint32(i))
+	}
+	return values, nil
+}
+
+// getChaosfsClient returns the secure client if the fuse server requires the token,
+// the sidecars injected before keep serving plain http
+func getChaosfsClient(ctx context.Context, client *channel.Client, pod *v1.Pod) (*chaosfs.ChaosBladeHookClient, error) {
+	return newFuseAuthSecrets(client).chaosfsClient(ctx, pod)
+}
+
+// fuseAuthSecrets caches the fuse auth secrets by the namespaces, so the secret of a namespace is read once
+// for the pods in it
+type fuseAuthSecrets struct {
+	client  *channel.Client
+	lock    sync.Mutex
+	secrets map[string]*v1.Secret
+}
+
+func newFuseAuthSecrets(client *channel.Client) *fuseAuthSecrets {
+	return &fuseAuthSecrets{client: client, secrets: make(map[string]*v1.Secret)}
+}
+
+func (s *fuseAuthSecrets) get(ctx context.Context, namespace string) (*v1.Secret, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if secret, ok := s.secrets[namespace]; ok {
+		return secret, nil
+	}
+	secret, err := s.client.CoreV1().Secrets(namespace).Get(ctx, webhook.FuseAuthSecretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get fuse auth secret error, %v", err)
+	}
+	s.secrets[namespace] = secret
+	return secret, nil
+}
+
+// chaosfsClient returns the client of the fuse server in the pod
+func (s *fuseAuthSecrets) chaosfsClient(ctx context.Context, pod *v1.Pod) (*chaosfs.ChaosBladeHookClient, error) {
+	port, err := getFuseServerPort(pod)
 	if err != nil {
 		return nil, err
 	}
 	addr := fmt.Sprintf("%s:%d", pod.Status.PodIP, port)
-	return chaosfs.NewChabladeHookClient(addr), nil
+	if !hasFuseAuth(pod) {
+		return chaosfs.NewChabladeHookClient(addr), nil
+	}
+	secret, err := s.get(ctx, pod.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return chaosfs.NewSecureChaosbladeHookClient(addr,
+		string(secret.Data[chaosfs.AuthTokenKey]), secret.Data[chaosfs.AuthCAKey])
 }
 
 func getContainerPort(portName string, pod *v1.Pod) (int32, error) {
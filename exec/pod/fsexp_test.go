@@ -0,0 +1,195 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pod
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/chaosblade-io/chaosblade-operator/channel"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/apis/chaosblade/v1alpha1"
+	chaosfs "github.com/chaosblade-io/chaosblade-operator/pkg/hookfs"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/runtime/chaosblade"
+	webhook "github.com/chaosblade-io/chaosblade-operator/pkg/webhook/pod"
+)
+
+// fakeIORuleClient keeps the injected rules, the injections with the failed delays fail
+type fakeIORuleClient struct {
+	rules        []chaosfs.InjectMessage
+	failedDelays map[uint32]bool
+	statusErr    error
+	revokeErr    error
+}
+
+func (c *fakeIORuleClient) InjectFault(ctx context.Context, injectMsg *chaosfs.InjectMessage) error {
+	if c.failedDelays[injectMsg.Delay] {
+		return errors.New("inject failed")
+	}
+	c.rules = append(c.rules, *injectMsg)
+	return nil
+}
+
+func (c *fakeIORuleClient) Revoke(ctx context.Context) error {
+	if c.revokeErr != nil {
+		return c.revokeErr
+	}
+	c.rules = nil
+	return nil
+}
+
+func (c *fakeIORuleClient) Status(ctx context.Context) ([]chaosfs.RuleStatus, error) {
+	if c.statusErr != nil {
+		return nil, c.statusErr
+	}
+	statuses := make([]chaosfs.RuleStatus, 0, len(c.rules))
+	for _, rule := range c.rules {
+		statuses = append(statuses, chaosfs.RuleStatus{InjectMessage: rule})
+	}
+	return statuses, nil
+}
+
+func Test_updateIORules(t *testing.T) {
+	previous := chaosfs.InjectMessage{Methods: []string{"read"}, Path: "/data", Delay: 100}
+	request := &chaosfs.InjectMessage{Methods: []string{"read"}, Path: "/data", Delay: 200}
+	tests := []struct {
+		name        string
+		client      *fakeIORuleClient
+		wantUpdated bool
+		wantSuccess bool
+		wantState   string
+		wantRules   []chaosfs.InjectMessage
+	}{
+		{
+			name:        "updated",
+			client:      &fakeIORuleClient{},
+			wantUpdated: true,
+			wantSuccess: true,
+			wantState:   v1alpha1.SuccessState,
+			wantRules:   []chaosfs.InjectMessage{previous, *request},
+		},
+		{
+			name:        "previous rules unknown",
+			client:      &fakeIORuleClient{statusErr: errors.New("status failed")},
+			wantSuccess: true,
+			wantState:   v1alpha1.SuccessState,
+			wantRules:   []chaosfs.InjectMessage{previous},
+		},
+		{
+			name:        "previous rules restored",
+			client:      &fakeIORuleClient{failedDelays: map[uint32]bool{200: true}},
+			wantSuccess: true,
+			wantState:   v1alpha1.SuccessState,
+			wantRules:   []chaosfs.InjectMessage{previous},
+		},
+		{
+			name:      "previous rules removed",
+			client:    &fakeIORuleClient{failedDelays: map[uint32]bool{100: true, 200: true}},
+			wantState: v1alpha1.DestroyedState,
+		},
+		{
+			name:      "revoke failed",
+			client:    &fakeIORuleClient{failedDelays: map[uint32]bool{200: true}, revokeErr: errors.New("revoke failed")},
+			wantState: v1alpha1.ErrorState,
+			wantRules: []chaosfs.InjectMessage{previous},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.client.rules = []chaosfs.InjectMessage{previous}
+			status, updated := updateIORules(context.Background(), logrus.NewEntry(logrus.New()), tt.client, request,
+				v1alpha1.ResourceStatus{Kind: v1alpha1.PodKind, Identifier: "default//pod"})
+			if updated != tt.wantUpdated || status.Success != tt.wantSuccess || status.State != tt.wantState {
+				t.Errorf("updateIORules() = %+v, %t, want success %t, state %s, updated %t",
+					status, updated, tt.wantSuccess, tt.wantState, tt.wantUpdated)
+			}
+			if !tt.wantUpdated && status.Error == "" {
+				t.Errorf("expected the error of the failed update")
+			}
+			if len(tt.client.rules) != len(tt.wantRules) || len(tt.wantRules) > 0 && !reflect.DeepEqual(tt.client.rules, tt.wantRules) {
+				t.Errorf("unexpected rules %+v, want %+v", tt.client.rules, tt.wantRules)
+			}
+		})
+	}
+}
+
+func Test_ioFaultLeaseTargets(t *testing.T) {
+	defer func(ttl time.Duration) { chaosblade.IOFaultTTL = ttl }(chaosblade.IOFaultTTL)
+	ioStatus := func(ttl string) v1alpha1.ExperimentStatus {
+		expSpec := v1alpha1.ExperimentSpec{Scope: "pod", Target: "pod", Action: "IO"}
+		if ttl != "" {
+			expSpec.Matchers = []v1alpha1.FlagSpec{{Name: "ttl", Value: []string{ttl}}}
+		}
+		return v1alpha1.ExperimentStatus{
+			Scope: "pod", Target: "pod", Action: "IO", AppliedSpec: &expSpec,
+			ResStatuses: []v1alpha1.ResourceStatus{
+				{Identifier: "default/node1/web-0", Success: true, State: v1alpha1.SuccessState},
+				{Identifier: "default/node1/web-1", Success: false, State: v1alpha1.ErrorState},
+			},
+		}
+	}
+	blades := []*v1alpha1.ChaosBlade{
+		{ObjectMeta: metav1.ObjectMeta{Name: "with-ttl"}, Status: v1alpha1.ChaosBladeStatus{
+			ExpStatuses: []v1alpha1.ExperimentStatus{ioStatus("5m")},
+		}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "without-ttl"}, Status: v1alpha1.ChaosBladeStatus{
+			ExpStatuses: []v1alpha1.ExperimentStatus{ioStatus("")},
+		}},
+	}
+	chaosblade.IOFaultTTL = 0
+	targets := ioFaultLeaseTargets(blades)
+	if len(targets) != 1 || targets[0].bladeName != "with-ttl" || targets[0].meta.PodName != "web-0" {
+		t.Errorf("expected only the successful target with ttl renewed, got %+v", targets)
+	}
+	// the operator default ttl applies to the experiments without the ttl flag
+	chaosblade.IOFaultTTL = time.Hour
+	if targets := ioFaultLeaseTargets(blades); len(targets) != 2 {
+		t.Errorf("expected the targets of both blades renewed, got %+v", targets)
+	}
+}
+
+func Test_fuseAuthSecrets(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: webhook.FuseAuthSecretName, Namespace: "default"},
+	})
+	gets := 0
+	clientset.PrependReactor("get", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		gets++
+		return false, nil, nil
+	})
+	secrets := newFuseAuthSecrets(&channel.Client{Interface: clientset})
+	for i := 0; i < 3; i++ {
+		if _, err := secrets.get(context.Background(), "default"); err != nil {
+			t.Fatalf("get the fuse auth secret failed, %v", err)
+		}
+	}
+	if gets != 1 {
+		t.Errorf("expected the secret read once, got %d", gets)
+	}
+	if _, err := secrets.get(context.Background(), "other"); err == nil {
+		t.Errorf("expected the missing secret failed")
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agent
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/chaosblade-io/chaosblade-operator/pkg/auth"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/certs"
+)
+
+const authValidity = 10 * 365 * 24 * time.Hour
+
+// EnsureAuthSecret creates the auth secret of the agent in the namespace if not exists
+func EnsureAuthSecret(ctx context.Context, clientset kubernetes.Interface, namespace string) error {
+	client := clientset.CoreV1().Secrets(namespace)
+	_, err := client.Get(ctx, AuthSecretName, metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+	secret, err := newAuthSecret(namespace)
+	if err != nil {
+		return err
+	}
+	logrus.WithField("namespace", namespace).Infoln("Create agent auth secret")
+	if _, err := client.Create(ctx, secret, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+func newAuthSecret(namespace string) (*corev1.Secret, error) {
+	token, err := auth.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+	ca, err := certs.GenerateCA("chaosblade-agent-ca", authValidity)
+	if err != nil {
+		return nil, err
+	}
+	serving, err := certs.GenerateServingCert(ca, AuthServerName, []string{AuthServerName}, authValidity)
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      AuthSecretName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "chaosblade-operator",
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			AuthTokenKey: []byte(token),
+			AuthCertKey:  serving.Cert,
+			AuthKeyKey:   serving.Key,
+			AuthCAKey:    ca.Cert,
+		},
+	}, nil
+}
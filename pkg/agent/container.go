@@ -0,0 +1,77 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// procRoot is the proc of the host, the agent runs with the host pid namespace
+var procRoot = "/proc"
+
+// findContainerPid returns the init process of the container, which is the process in the
+// container cgroup whose parent is not
+func findContainerPid(containerId string) (int, error) {
+	entries, err := os.ReadDir(procRoot)
+	if err != nil {
+		return 0, err
+	}
+	parents := make(map[int]int)
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		cgroup, err := os.ReadFile(path.Join(procRoot, entry.Name(), "cgroup"))
+		if err != nil || !strings.Contains(string(cgroup), containerId) {
+			continue
+		}
+		parents[pid] = readParentPid(pid)
+	}
+	initPid := 0
+	for pid, ppid := range parents {
+		if _, ok := parents[ppid]; ok {
+			continue
+		}
+		if initPid == 0 || pid < initPid {
+			initPid = pid
+		}
+	}
+	if initPid == 0 {
+		return 0, fmt.Errorf("process of container %s not found", containerId)
+	}
+	return initPid, nil
+}
+
+// readParentPid reads the parent pid in the stat, the fields after the command in parentheses
+// are the state and the parent pid
+func readParentPid(pid int) int {
+	stat, err := os.ReadFile(path.Join(procRoot, strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+	if len(fields) < 2 {
+		return 0
+	}
+	ppid, _ := strconv.Atoi(fields[1])
+	return ppid
+}
@@ -0,0 +1,163 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os/exec"
+	"path"
+	"strconv"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaosblade-operator/pkg/auth"
+)
+
+// Server runs the commands requested by the operator in the agent container, or in the
+// namespaces of the containers on the node by the nsexec
+type Server struct {
+	addr    string
+	authDir string
+	nsexec  string
+}
+
+// NewServer returns the agent server, the requests are authenticated by the token in the authDir
+// and served over tls with the certificate in it. The authDir is required unless insecure for testing
+func NewServer(addr, authDir, nsexec string) *Server {
+	return &Server{
+		addr:    addr,
+		authDir: authDir,
+		nsexec:  nsexec,
+	}
+}
+
+func (s *Server) Start(stop context.Context) error {
+	var execHandler http.Handler = http.HandlerFunc(s.ExecHandler)
+	if s.authDir != "" {
+		token, err := auth.ReadToken(path.Join(s.authDir, AuthTokenKey))
+		if err != nil {
+			return err
+		}
+		execHandler = auth.RequireToken(token, execHandler)
+	}
+	mux := http.NewServeMux()
+	mux.Handle(ExecPath, execHandler)
+	mux.HandleFunc(HealthPath, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	server := &http.Server{
+		Addr:    s.addr,
+		Handler: mux,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		if s.authDir != "" {
+			errCh <- server.ListenAndServeTLS(path.Join(s.authDir, AuthCertKey), path.Join(s.authDir, AuthKeyKey))
+			return
+		}
+		errCh <- server.ListenAndServe()
+	}()
+	select {
+	case <-stop.Done():
+		return server.Shutdown(context.Background())
+	case err := <-errCh:
+		return err
+	}
+}
+
+// ExecHandler runs the command until it exits or the request is canceled, and streams the output
+func (s *Server) ExecHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	query := r.URL.Query()
+	command := query[CommandParam]
+	if len(command) == 0 {
+		http.Error(w, "command is required", http.StatusBadRequest)
+		return
+	}
+	logFields := logrus.WithFields(logrus.Fields{
+		"command":     command,
+		"containerId": query.Get(ContainerIdParam),
+		"remote":      r.RemoteAddr,
+	})
+	if containerId := query.Get(ContainerIdParam); containerId != "" {
+		pid, err := findContainerPid(containerId)
+		if err != nil {
+			logFields.WithError(err).Warningln("Find container pid failed")
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		command = append([]string{s.nsexec, "-t", strconv.Itoa(pid), "-p", "-m", "-n", "--"}, command...)
+	}
+	// the stdin is read while the output is written
+	if err := http.NewResponseController(w).EnableFullDuplex(); err != nil {
+		logFields.WithError(err).Debugln("Enable full duplex failed")
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	encoder := &frameEncoder{encoder: json.NewEncoder(w), flusher: http.NewResponseController(w)}
+	cmd := exec.CommandContext(r.Context(), command[0], command[1:]...)
+	if query.Get(StdinParam) == "true" {
+		cmd.Stdin = r.Body
+	}
+	cmd.Stdout = &frameWriter{stream: StdoutStream, encoder: encoder}
+	cmd.Stderr = &frameWriter{stream: StderrStream, encoder: encoder}
+	logFields.Infoln("Exec command")
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		logFields.WithError(err).Warningln("Exec command failed")
+		encoder.encode(&Frame{Error: err.Error()})
+		return
+	}
+	exitCode := cmd.ProcessState.ExitCode()
+	encoder.encode(&Frame{ExitCode: &exitCode})
+}
+
+// frameEncoder writes the frames of stdout and stderr in order
+type frameEncoder struct {
+	lock    sync.Mutex
+	encoder *json.Encoder
+	flusher *http.ResponseController
+}
+
+func (e *frameEncoder) encode(frame *Frame) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.encoder.Encode(frame); err != nil {
+		return err
+	}
+	return e.flusher.Flush()
+}
+
+type frameWriter struct {
+	stream  string
+	encoder *frameEncoder
+}
+
+func (w *frameWriter) Write(p []byte) (int, error) {
+	if err := w.encoder.encode(&Frame{Stream: w.stream, Data: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
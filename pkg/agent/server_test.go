@@ -0,0 +1,83 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agent
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestServer_ExecHandler(t *testing.T) {
+	query := url.Values{
+		CommandParam: []string{"sh", "-c", "cat; echo failed >&2; exit 3"},
+		StdinParam:   []string{"true"},
+	}
+	request := httptest.NewRequest(http.MethodPost, ExecPath+"?"+query.Encode(), strings.NewReader("input"))
+	recorder := httptest.NewRecorder()
+	NewServer(":0", "", "nsexec").ExecHandler(recorder, request)
+
+	decoder := json.NewDecoder(recorder.Body)
+	output := map[string]string{}
+	var last Frame
+	for decoder.More() {
+		frame := Frame{}
+		if err := decoder.Decode(&frame); err != nil {
+			t.Fatalf("decode frame failed, %v", err)
+		}
+		output[frame.Stream] += string(frame.Data)
+		last = frame
+	}
+	if output[StdoutStream] != "input" || output[StderrStream] != "failed\n" {
+		t.Errorf("unexpected output %v", output)
+	}
+	if last.ExitCode == nil || *last.ExitCode != 3 {
+		t.Errorf("unexpected last frame %+v", last)
+	}
+}
+
+func Test_findContainerPid(t *testing.T) {
+	procRoot = t.TempDir()
+	defer func() { procRoot = "/proc" }()
+	processes := []struct {
+		pid, ppid, cgroup string
+	}{
+		{"1", "0", "0::/init.scope"},
+		{"100", "90", "0::/kubepods/pod1/cri-containerd-abcdef.scope"},
+		{"101", "100", "0::/kubepods/pod1/cri-containerd-abcdef.scope"},
+		{"90", "1", "0::/system.slice/containerd.service"},
+	}
+	for _, p := range processes {
+		dir := path.Join(procRoot, p.pid)
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		os.WriteFile(path.Join(dir, "cgroup"), []byte(p.cgroup+"\n"), 0644)
+		os.WriteFile(path.Join(dir, "stat"), []byte(p.pid+" (sh (x)) S "+p.ppid+" 1 1 0"), 0644)
+	}
+	if pid, err := findContainerPid("abcdef"); err != nil || pid != 100 {
+		t.Errorf("unexpected container pid %d, %v", pid, err)
+	}
+	if _, err := findContainerPid("notfound"); err == nil {
+		t.Errorf("expected the container not found")
+	}
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agent
+
+const (
+	// ExecPath runs the command, the request body is the stdin and the response is the stream of frames
+	ExecPath   = "/exec"
+	HealthPath = "/healthz"
+
+	DefaultPort = 19527
+)
+
+// the query parameters of the exec request
+const (
+	// ContainerIdParam is the container in which the command runs, the agent container if empty
+	ContainerIdParam = "containerId"
+	CommandParam     = "command"
+	StdinParam       = "stdin"
+)
+
+const (
+	// AuthSecretName is the secret contains the token and the certificates of the agent in the chaosblade namespace
+	AuthSecretName = "chaosblade-agent-auth"
+	AuthServerName = "chaosblade-agent"
+	AuthTokenKey   = "token"
+	AuthCertKey    = "tls.crt"
+	AuthKeyKey     = "tls.key"
+	AuthCAKey      = "ca.crt"
+)
+
+const (
+	StdoutStream = "stdout"
+	StderrStream = "stderr"
+)
+
+// Frame is a chunk of the output or the result of the command, encoded as a json line.
+// The last frame has the exit code or the error
+type Frame struct {
+	Stream   string `json:"stream,omitempty"`
+	Data     []byte `json:"data,omitempty"`
+	ExitCode *int   `json:"exitCode,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
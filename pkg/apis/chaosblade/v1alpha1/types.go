@@ -29,22 +29,37 @@ const (
 	ClusterPhaseInitial     ClusterPhase = ""
 	ClusterPhaseInitialized ClusterPhase = "Initialized"
 	ClusterPhaseRunning     ClusterPhase = "Running"
-	ClusterPhaseUpdating    ClusterPhase = "Updating"
-	ClusterPhaseDestroying  ClusterPhase = "Destroying"
-	ClusterPhaseDestroyed   ClusterPhase = "Destroyed"
-	ClusterPhaseError       ClusterPhase = "Error"
+	// ClusterPhasePartiallyRunning means some targets or experiments failed, but the others are running
+	ClusterPhasePartiallyRunning ClusterPhase = "PartiallyRunning"
+	ClusterPhaseUpdating         ClusterPhase = "Updating"
+	ClusterPhaseDestroying       ClusterPhase = "Destroying"
+	ClusterPhaseDestroyed        ClusterPhase = "Destroyed"
+	ClusterPhaseError            ClusterPhase = "Error"
 )
 
 // ChaosBladeSpec defines the desired state of ChaosBlade
 // +k8s:openapi-gen=true
 type ChaosBladeSpec struct {
-	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
-	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
-	// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
+	// Experiments are the experiments of the chaosblade. The changed experiments of the running chaosblade are
+	// destroyed and created again, only the pod IO experiments are updated in place if the targets and the
+	// timeout are not changed. The names of the experiments must be unique
+	// +kubebuilder:validation:MaxItems=100
+	// +kubebuilder:validation:XValidation:rule="self.all(x, !has(x.name) || x.name == '' || self.filter(y, has(y.name) && y.name == x.name).size() == 1)",message="the experiment names must be unique"
 	Experiments []ExperimentSpec `json:"experiments"`
+	// Parallelism is the max number of the experiments created concurrently, the operator default is used if 0
+	Parallelism int32 `json:"parallelism,omitempty"`
+	// StartBarrier creates all experiments concurrently and starts the injections after the targets of all
+	// experiments are selected, so that the experiments overlap in time
+	StartBarrier bool `json:"startBarrier,omitempty"`
 }
 
 type ExperimentSpec struct {
+	// Name is the unique name of the experiment in the chaosblade, the status is correlated with the spec by it.
+	// The experiments of the chaosblades created by the older operator are named by the target and the action
+	// in the status on upgrade, the spec is not changed
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	Name string `json:"name"`
 	// Scope is the area of the experiments, currently support node, pod and container
 	Scope string `json:"scope"`
 	// Target is the experiment target, such as cpu, network
@@ -55,6 +70,20 @@ type ExperimentSpec struct {
 	Desc string `json:"desc,omitempty"`
 	// Matchers is the experiment rules
 	Matchers []FlagSpec `json:"matchers,omitempty"`
+	// Ramp injects the experiment into the selected targets gradually by the steps, such as 1 target, then 10%
+	// and 50% of the targets. All targets are injected at once if empty
+	Ramp []RampStep `json:"ramp,omitempty"`
+}
+
+// RampStep injects the experiment into more targets up to the count or the percent of the selected targets,
+// the next step starts after the hold
+type RampStep struct {
+	// Count is the number of the targets injected by the end of the step
+	Count int32 `json:"count,omitempty"`
+	// Percent is the percent of the selected targets injected by the end of the step, used if Count is 0
+	Percent int32 `json:"percent,omitempty"`
+	// Hold is the wait before the next step, such as 1m
+	Hold metav1.Duration `json:"hold,omitempty"`
 }
 
 type FlagSpec struct {
@@ -69,7 +98,7 @@ type FlagSpec struct {
 // +k8s:openapi-gen=true
 type ChaosBladeStatus struct {
 	// Phase indicates the state of the experiment
-	//   Initial -> Running -> Updating -> Destroying -> Destroyed
+	//   Initial -> Running/PartiallyRunning -> Updating -> Destroying -> Destroyed
 	Phase ClusterPhase `json:"phase,omitempty"`
 
 	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
@@ -115,6 +144,21 @@ type ResourceStatus struct {
 	// container: Namespace/NodeName/PodName/ContainerName
 	// pod： Namespace/NodeName/PodName
 	Identifier string `json:"identifier,omitempty"`
+
+	// Attempts is the number of the command executions, more than 1 if retried after the transient errors
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// IOFaultStatistics is the hit counters of the IO fault rules, only for pod IO experiments
+	IOFaultStatistics *IOFaultStatistics `json:"ioFaultStatistics,omitempty"`
+}
+
+type IOFaultStatistics struct {
+	// Matched is the count of the file system calls selected by the rules
+	Matched int64 `json:"matched"`
+	// Injected is the count of the file system calls the fault was injected into
+	Injected int64 `json:"injected"`
+	// LastHitTime is the time of the last injection
+	LastHitTime *metav1.Time `json:"lastHitTime,omitempty"`
 }
 
 const (
@@ -147,6 +191,8 @@ func CreateFailResStatuses(code int32, err, uid string) []ResourceStatus {
 }
 
 type ExperimentStatus struct {
+	// Name is the name of the experiment in the spec
+	Name string `json:"name,omitempty"`
 	// experiment scope for cache
 	Scope  string `json:"scope"`
 	Target string `json:"target"`
@@ -158,6 +204,45 @@ type ExperimentStatus struct {
 	Error string `json:"error,omitempty"`
 	// ResStatuses is the details of the experiment
 	ResStatuses []ResourceStatus `json:"resStatuses,omitempty"`
+
+	// AppliedSpec is the snapshot of the experiment spec applied to the targets, the spec change is applied by
+	// the diff with it
+	AppliedSpec *ExperimentSpec `json:"appliedSpec,omitempty"`
+
+	// Retries is the times the experiment was injected again into the failed targets
+	Retries int32 `json:"retries,omitempty"`
+	// NextRetryTime is the time to inject the experiment again into the failed targets, nil if not retried anymore
+	NextRetryTime *metav1.Time `json:"nextRetryTime,omitempty"`
+
+	// Ramp is the progress of the gradual injection, only for the experiments with the ramp steps
+	Ramp *RampStatus `json:"ramp,omitempty"`
+}
+
+const (
+	RampRunningState   = "Running"
+	RampCompletedState = "Completed"
+	RampStoppedState   = "Stopped"
+)
+
+type RampStatus struct {
+	// State is Running until all steps are executed, Stopped if a step failed
+	State string `json:"state"`
+	// Targets are the names of the pods or the nodes selected by the experiment, injected in order
+	Targets []string `json:"targets,omitempty"`
+	// Steps are the results of the executed steps
+	Steps []RampStepStatus `json:"steps,omitempty"`
+	// NextStepTime is the time to execute the next step, nil if the ramp is completed or stopped
+	NextStepTime *metav1.Time `json:"nextStepTime,omitempty"`
+}
+
+type RampStepStatus struct {
+	// Injected is the number of the targets injected by the end of the step
+	Injected int32 `json:"injected"`
+	// Success is false if the injection of the step failed
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+	// Time is the time the step was executed
+	Time metav1.Time `json:"time"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
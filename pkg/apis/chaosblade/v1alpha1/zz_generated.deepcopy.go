@@ -142,6 +142,11 @@ func (in *ExperimentSpec) DeepCopyInto(out *ExperimentSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Ramp != nil {
+		in, out := &in.Ramp, &out.Ramp
+		*out = make([]RampStep, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -161,7 +166,23 @@ func (in *ExperimentStatus) DeepCopyInto(out *ExperimentStatus) {
 	if in.ResStatuses != nil {
 		in, out := &in.ResStatuses, &out.ResStatuses
 		*out = make([]ResourceStatus, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AppliedSpec != nil {
+		in, out := &in.AppliedSpec, &out.AppliedSpec
+		*out = new(ExperimentSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NextRetryTime != nil {
+		in, out := &in.NextRetryTime, &out.NextRetryTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Ramp != nil {
+		in, out := &in.Ramp, &out.Ramp
+		*out = new(RampStatus)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -197,9 +218,100 @@ func (in *FlagSpec) DeepCopy() *FlagSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IOFaultStatistics) DeepCopyInto(out *IOFaultStatistics) {
+	*out = *in
+	if in.LastHitTime != nil {
+		in, out := &in.LastHitTime, &out.LastHitTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IOFaultStatistics.
+func (in *IOFaultStatistics) DeepCopy() *IOFaultStatistics {
+	if in == nil {
+		return nil
+	}
+	out := new(IOFaultStatistics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RampStatus) DeepCopyInto(out *RampStatus) {
+	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]RampStepStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NextStepTime != nil {
+		in, out := &in.NextStepTime, &out.NextStepTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RampStatus.
+func (in *RampStatus) DeepCopy() *RampStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RampStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RampStep) DeepCopyInto(out *RampStep) {
+	*out = *in
+	out.Hold = in.Hold
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RampStep.
+func (in *RampStep) DeepCopy() *RampStep {
+	if in == nil {
+		return nil
+	}
+	out := new(RampStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RampStepStatus) DeepCopyInto(out *RampStepStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RampStepStatus.
+func (in *RampStepStatus) DeepCopy() *RampStepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RampStepStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceStatus) DeepCopyInto(out *ResourceStatus) {
 	*out = *in
+	if in.IOFaultStatistics != nil {
+		in, out := &in.IOFaultStatistics, &out.IOFaultStatistics
+		*out = new(IOFaultStatistics)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
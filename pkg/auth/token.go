@@ -0,0 +1,71 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// BearerPrefix is the prefix of the token in the Authorization header
+const BearerPrefix = "Bearer "
+
+// GenerateToken returns a random token in hex
+func GenerateToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("generate auth token error, %v", err)
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// ReadToken reads the bearer token from the file
+func ReadToken(file string) (string, error) {
+	bytes, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("read auth token error, %v", err)
+	}
+	token := strings.TrimSpace(string(bytes))
+	if token == "" {
+		return "", fmt.Errorf("auth token is empty")
+	}
+	return token, nil
+}
+
+// RequireToken rejects the requests without the expected bearer token
+func RequireToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		if !strings.HasPrefix(authorization, BearerPrefix) ||
+			subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(authorization, BearerPrefix)), []byte(token)) != 1 {
+			logrus.WithFields(logrus.Fields{
+				"path":   r.URL.Path,
+				"remote": r.RemoteAddr,
+			}).Warningln("Reject unauthorized request")
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
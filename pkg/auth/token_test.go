@@ -0,0 +1,74 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestRequireToken(t *testing.T) {
+	handler := RequireToken("secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	tests := []struct {
+		name          string
+		authorization string
+		want          int
+	}{
+		{name: "no token", authorization: "", want: http.StatusUnauthorized},
+		{name: "wrong token", authorization: "Bearer other", want: http.StatusUnauthorized},
+		{name: "not bearer", authorization: "Basic secret", want: http.StatusUnauthorized},
+		{name: "token prefix", authorization: "Bearer secre", want: http.StatusUnauthorized},
+		{name: "valid token", authorization: "Bearer secret", want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/status", nil)
+			if tt.authorization != "" {
+				request.Header.Set("Authorization", tt.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			if recorder.Code != tt.want {
+				t.Errorf("status code = %d, want %d", recorder.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadToken(t *testing.T) {
+	file := path.Join(t.TempDir(), "token")
+	if _, err := ReadToken(file); err == nil {
+		t.Errorf("ReadToken() without the token file succeeded")
+	}
+	if err := os.WriteFile(file, []byte(" \n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadToken(file); err == nil {
+		t.Errorf("ReadToken() with the empty token succeeded")
+	}
+	if err := os.WriteFile(file, []byte("secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if token, err := ReadToken(file); err != nil || token != "secret" {
+		t.Errorf("ReadToken() = %q, %v, want secret", token, err)
+	}
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+	"time"
+)
+
+func TestGenerateServingCert(t *testing.T) {
+	ca, err := GenerateCA("test-ca", time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateCA() error = %v", err)
+	}
+	serving, err := GenerateServingCert(ca, "test-server", []string{"test-server"}, time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateServingCert() error = %v", err)
+	}
+	if _, err := tls.X509KeyPair(serving.Cert, serving.Key); err != nil {
+		t.Fatalf("invalid serving key pair, %v", err)
+	}
+	cert, err := parseCert(serving.Cert)
+	if err != nil {
+		t.Fatalf("parse serving certificate error, %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(ca.Cert)
+	if _, err := cert.Verify(x509.VerifyOptions{DNSName: "test-server", Roots: pool}); err != nil {
+		t.Errorf("the serving certificate is not trusted by the ca, %v", err)
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{DNSName: "other-server", Roots: pool}); err == nil {
+		t.Errorf("the serving certificate is trusted for another name")
+	}
+	other, err := GenerateCA("other-ca", time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateCA() error = %v", err)
+	}
+	otherPool := x509.NewCertPool()
+	otherPool.AppendCertsFromPEM(other.Cert)
+	if _, err := cert.Verify(x509.VerifyOptions{DNSName: "test-server", Roots: otherPool}); err == nil {
+		t.Errorf("the serving certificate is trusted by another ca")
+	}
+
+	notAfter, err := NotAfter(serving.Cert)
+	if err != nil {
+		t.Fatalf("NotAfter() error = %v", err)
+	}
+	if d := time.Until(notAfter); d <= 0 || d > time.Hour {
+		t.Errorf("NotAfter() = %v, want within the validity", notAfter)
+	}
+}
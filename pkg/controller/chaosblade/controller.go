@@ -18,14 +18,16 @@ package chaosblade
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -52,13 +54,33 @@ const chaosbladeFinalizer = "finalizer.chaosblade.io"
 // Add creates a new ChaosBlade Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
-	if err := add(mgr, newReconciler(mgr)); err != nil {
+	rcb := newReconciler(mgr)
+	if err := add(mgr, rcb); err != nil {
+		return err
+	}
+	// recover before reconciling, otherwise the chaosblade with the lost status is created again. The client
+	// reads from the cache which is available after the manager starts, so the reconciler waits for the recovery
+	if err := mgr.Add(&startupRecoverer{cache: mgr.GetCache(), rcb: rcb}); err != nil {
 		return err
 	}
 	// add periodically clean up blade ticker
-	return mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
 		startPeriodicallyCleanUpBlade(ctx, mgr)
 		return nil
+	})); err != nil {
+		return err
+	}
+	// add sweeping orphan experiments ticker
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		startSweepOrphanExperiments(ctx, mgr)
+		return nil
+	})); err != nil {
+		return err
+	}
+	// add renewing io fault lease ticker
+	return mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		startRenewIOFaultLease(ctx, mgr)
+		return nil
 	}))
 }
 
@@ -66,12 +88,40 @@ func Add(mgr manager.Manager) error {
 func newReconciler(mgr manager.Manager) *ReconcileChaosBlade {
 	cbClient := mgr.GetClient().(*channel.Client)
 	return &ReconcileChaosBlade{
-		client:   cbClient,
-		scheme:   mgr.GetScheme(),
-		Executor: exec.NewDispatcherExecutor(cbClient),
+		client:    cbClient,
+		scheme:    mgr.GetScheme(),
+		recorder:  mgr.GetEventRecorderFor("chaosblade-operator"),
+		Executor:  exec.NewDispatcherExecutor(cbClient),
+		recovered: make(chan struct{}),
 	}
 }
 
+// cacheSyncer is the cache of the manager, which serves the reads of the client after it is synced
+type cacheSyncer interface {
+	WaitForCacheSync(ctx context.Context) bool
+}
+
+// startupRecoverer is the runnable of the manager which recovers the journals and migrates the statuses
+// written by the older operator after the cache is synced, and then releases the reconciler
+type startupRecoverer struct {
+	cache cacheSyncer
+	rcb   *ReconcileChaosBlade
+}
+
+func (s *startupRecoverer) Start(ctx context.Context) error {
+	defer close(s.rcb.recovered)
+	if !s.cache.WaitForCacheSync(ctx) {
+		return errors.New("wait for the cache sync failed before recovering the chaosblades")
+	}
+	recoverCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+	if chaosblade.JournalEnable {
+		s.rcb.recoverJournals(recoverCtx)
+	}
+	s.rcb.migrateAppliedSpecs(recoverCtx)
+	return nil
+}
+
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, rcb *ReconcileChaosBlade) error {
 	// Create a new controller
@@ -94,6 +144,11 @@ func add(mgr manager.Manager, rcb *ReconcileChaosBlade) error {
 	if err != nil {
 		return err
 	}
+	if chaosblade.StickyEnable {
+		if err := addStickyController(mgr, rcb); err != nil {
+			return err
+		}
+	}
 	if chaosblade.DaemonsetEnable {
 		//namespace, err := k8sutil.GetOperatorNamespace()
 		//if err != nil {
@@ -175,7 +230,11 @@ type ReconcileChaosBlade struct {
 	// that reads objects from the cache and writes to the apiserver
 	client   *channel.Client
 	scheme   *runtime.Scheme
+	recorder record.EventRecorder
 	Executor model.ExpController
+	// recovered is closed after the journals are recovered and the statuses are migrated on startup, the
+	// reconciler does not wait if nil
+	recovered chan struct{}
 }
 
 // Reconcile reads that state of the cluster for a ChaosBlade object and makes changes based on the state read
@@ -186,12 +245,22 @@ type ReconcileChaosBlade struct {
 func (r *ReconcileChaosBlade) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
 	reqLogger := logrus.WithField("Request.Name", request.Name)
 	forget := reconcile.Result{}
+	if r.recovered != nil {
+		select {
+		case <-r.recovered:
+		case <-ctx.Done():
+			return forget, ctx.Err()
+		}
+	}
 	// Fetch the RC instance
 	cb := &v1alpha1.ChaosBlade{}
 	err := r.client.Get(ctx, request.NamespacedName, cb)
 	if err != nil {
 		return forget, nil
 	}
+	// the status is correlated with the spec by the names, the legacy experiments are named in memory only, so
+	// the spec must not be written
+	nameLegacyExperiments(cb)
 	if len(cb.Spec.Experiments) == 0 {
 		return forget, nil
 	}
@@ -200,8 +269,9 @@ func (r *ReconcileChaosBlade) Reconcile(ctx context.Context, request reconcile.R
 	// Destroyed->delete
 	// Remove the Finalizer if the CR object status is destroyed to delete it
 	if cb.Status.Phase == v1alpha1.ClusterPhaseDestroyed {
+		patch := client.MergeFrom(cb.DeepCopy())
 		cb.SetFinalizers(remove(cb.GetFinalizers(), chaosbladeFinalizer))
-		err := r.client.Update(ctx, cb)
+		err := r.client.Patch(ctx, cb, patch)
 		if err != nil {
 			reqLogger.WithError(err).Errorln("remove chaosblade finalizer failed at destroyed phase")
 		}
@@ -214,6 +284,12 @@ func (r *ReconcileChaosBlade) Reconcile(ctx context.Context, request reconcile.R
 		}
 		return forget, nil
 	}
+	if err := validateExperiments(cb.Spec); err != nil {
+		// the spec is not applied until it is corrected
+		reqLogger.WithError(err).Errorln("invalid chaosblade spec")
+		r.recorder.Eventf(cb, corev1.EventTypeWarning, InvalidSpecReason, "%v", err)
+		return forget, nil
+	}
 	// Initial->Initialized
 	if cb.Status.Phase == v1alpha1.ClusterPhaseInitial {
 		if contains(cb.GetFinalizers(), chaosbladeFinalizer) {
@@ -223,9 +299,9 @@ func (r *ReconcileChaosBlade) Reconcile(ctx context.Context, request reconcile.R
 				reqLogger.WithError(err).Errorln("update chaosblade phase to Initialized failed")
 			}
 		} else {
+			patch := client.MergeFrom(cb.DeepCopy())
 			cb.SetFinalizers(append(cb.GetFinalizers(), chaosbladeFinalizer))
-			// Update CR
-			if err := r.client.Update(ctx, cb); err != nil {
+			if err := r.client.Patch(ctx, cb, patch); err != nil {
 				reqLogger.WithError(err).Errorln("add finalizer to chaosblade failed")
 			}
 		}
@@ -236,58 +312,62 @@ func (r *ReconcileChaosBlade) Reconcile(ctx context.Context, request reconcile.R
 	if cb.Status.Phase == v1alpha1.ClusterPhaseInitialized ||
 		cb.Status.Phase == v1alpha1.ClusterPhaseUpdating {
 		originalPhase := cb.Status.Phase
-		expStatusList := make([]v1alpha1.ExperimentStatus, 0)
-		phase := v1alpha1.ClusterPhaseError
-		for _, exp := range cb.Spec.Experiments {
-			experimentStatus := r.Executor.Create(cb.Name, exp)
-			if experimentStatus.Success {
-				phase = v1alpha1.ClusterPhaseRunning
-			}
-			expStatusList = append(expStatusList, experimentStatus)
+		if err := model.BeginJournal(ctx, r.client, cb); err != nil {
+			reqLogger.WithError(err).Errorln("write the injection journal failed")
+			return forget, err
+		}
+		expStatusList := r.createExperiments(reqLogger, cb, cb.Spec.Experiments,
+			experimentParallelism(cb), cb.Spec.StartBarrier)
+		for i, exp := range cb.Spec.Experiments {
+			expStatusList[i] = scheduleRetry(exp, expStatusList[i], time.Now())
 		}
+		phase := bladePhase(expStatusList)
 		cb.Status.ExpStatuses = expStatusList
 		cb.Status.Phase = phase
 		if err := r.client.Status().Update(ctx, cb); err != nil {
 			reqLogger.WithError(err).Errorf("Important!!!!!update phase from %s to %s failed", originalPhase, phase)
+			// keep the journal to recover the injections
+			return forget, nil
 		}
-		return forget, nil
+		if err := model.CommitJournal(ctx, r.client, cb.Name); err != nil {
+			reqLogger.WithError(err).Warnln("delete the injection journal failed")
+		}
+		return retryResult(cb), nil
 	}
 
-	// Running/Error->Updating/Destroying
-	if cb.Status.Phase == v1alpha1.ClusterPhaseRunning ||
+	// Running/PartiallyRunning/Error->Running/PartiallyRunning/Error
+	if isRunningPhase(cb.Status.Phase) ||
 		cb.Status.Phase == v1alpha1.ClusterPhaseError {
-		// Update CR, firstly destroy it and re-create the new CR
-		phase := v1alpha1.ClusterPhaseUpdating
-		originalPhase := cb.Status.Phase
-		logrus.Infof("update cb: %+v", *cb)
-		matchersString := cb.GetAnnotations()["preSpec"]
-		if matchersString != "" {
-			var oldSpec v1alpha1.ChaosBladeSpec
-			err := json.Unmarshal([]byte(matchersString), &oldSpec)
-			if err != nil {
-				reqLogger.WithError(err).Errorf("unmarshal old spec failed, %s", matchersString)
-				return forget, nil
-			}
-			// update annotation to cb
-			if err = r.client.Update(ctx, cb); err != nil {
-				reqLogger.WithError(err).Errorln("add annotation to chaosblade failed")
-			}
-			if cb.Status.ExpStatuses != nil {
-				for idx, expStatus := range cb.Status.ExpStatuses {
-					experimentStatus := r.Executor.Destroy(cb.Name, oldSpec.Experiments[idx], expStatus)
-					if !experimentStatus.Success {
-						phase = v1alpha1.ClusterPhaseDestroying
-					}
-					cb.Status.ExpStatuses[idx] = experimentStatus
-				}
+		// Update CR, only the changed experiments are updated, destroyed or created
+		if !isSpecApplied(cb) {
+			originalPhase := cb.Status.Phase
+			if err := model.BeginJournal(ctx, r.client, cb); err != nil {
+				reqLogger.WithError(err).Errorln("write the injection journal failed")
+				return forget, err
 			}
+			appliedSpec := v1alpha1.ChaosBladeSpec{Experiments: appliedExperiments(cb)}
+			cb.Status.ExpStatuses = r.updateExperiments(reqLogger, cb, appliedSpec, cb.Spec, cb.Status.ExpStatuses,
+				experimentParallelism(cb))
+			phase := bladePhase(cb.Status.ExpStatuses)
 			cb.Status.Phase = phase
 			if err := r.client.Status().Update(ctx, cb); err != nil {
-				reqLogger.WithError(err).Errorf("update phase from %s to %s failed", originalPhase, phase)
+				reqLogger.WithError(err).Errorf("Important!!!!!update phase from %s to %s failed", originalPhase, phase)
+				// keep the journal to recover the injections
+				return forget, nil
 			}
-			return forget, nil
+			if err := model.CommitJournal(ctx, r.client, cb.Name); err != nil {
+				reqLogger.WithError(err).Warnln("delete the injection journal failed")
+			}
+			if !isSpecApplied(cb) {
+				// the old experiments failed to destroy are applied again later
+				return reconcile.Result{RequeueAfter: chaosblade.RetryFailedBackoff}, nil
+			}
+			return retryResult(cb), nil
+		}
+		// PartiallyRunning/Error->Running/PartiallyRunning/Error
+		if hasPendingInjection(cb) {
+			return r.injectPendingTargets(ctx, reqLogger, cb)
 		}
-		reqLogger.Errorln("can not found matchers in annotations field")
 	}
 	return forget, nil
 }
@@ -296,9 +376,9 @@ func (r *ReconcileChaosBlade) Reconcile(ctx context.Context, request reconcile.R
 func (r *ReconcileChaosBlade) finalizeChaosBlade(ctx context.Context, reqLogger *logrus.Entry, cb *v1alpha1.ChaosBlade) error {
 	phase := v1alpha1.ClusterPhaseDestroyed
 	reqLogger.Infoln("Finalize the chaosblade")
-	if cb.Status.ExpStatuses != nil &&
-		len(cb.Spec.Experiments) == len(cb.Status.ExpStatuses) {
-		for idx, exp := range cb.Spec.Experiments {
+	if cb.Status.ExpStatuses != nil {
+		// destroy by the applied spec, the spec may be changed but not applied yet
+		for idx, exp := range appliedExperiments(cb) {
 			oldExpStatus := cb.Status.ExpStatuses[idx]
 			oldExpStatus = r.Executor.Destroy(cb.Name, exp, oldExpStatus)
 			if !oldExpStatus.Success {
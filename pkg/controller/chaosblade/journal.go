@@ -0,0 +1,188 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chaosblade
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/chaosblade-io/chaosblade-operator/exec/model"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/apis/chaosblade/v1alpha1"
+)
+
+// recoverJournals recovers the injections of the journals left by the last operator. The injections are
+// adopted by the chaosblade if its status is lost, otherwise they are destroyed if not in the status.
+func (r *ReconcileChaosBlade) recoverJournals(ctx context.Context) {
+	journals, err := model.ListJournals(ctx, r.client)
+	if err != nil {
+		logrus.WithError(err).Errorln("list the injection journals failed")
+		return
+	}
+	for _, journal := range journals {
+		logFields := logrus.WithField("blade", journal.BladeName)
+		if err := r.recoverJournal(ctx, logFields, journal); err != nil {
+			logFields.WithError(err).Errorln("recover the injection journal failed, retry it on the next startup")
+			continue
+		}
+		if err := model.CommitJournal(ctx, r.client, journal.BladeName); err != nil {
+			logFields.WithError(err).Warnln("delete the recovered injection journal failed")
+		}
+	}
+}
+
+func (r *ReconcileChaosBlade) recoverJournal(ctx context.Context, logFields *logrus.Entry, journal model.Journal) error {
+	cb := &v1alpha1.ChaosBlade{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: journal.BladeName}, cb)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	found := err == nil
+	nameLegacyExperiments(cb)
+	for _, entry := range journal.Entries {
+		if entry.State == model.JournalPendingState {
+			logFields.WithField("identifier", entry.Identifier).
+				Warnln("the injection result is lost, it is unknown whether the experiment was created in the target")
+		}
+	}
+	bladeSpec := journal.Spec
+	if found {
+		bladeSpec = &cb.Spec
+	}
+	// the status update is lost after the experiments are created, the chaosblade will create them again
+	if found && cb.GetDeletionTimestamp() == nil &&
+		(cb.Status.Phase == v1alpha1.ClusterPhaseInitialized || cb.Status.Phase == v1alpha1.ClusterPhaseUpdating) {
+		expStatuses, orphans, adopted := adoptInjections(cb.Spec, journal.Entries)
+		if err := r.destroyInjections(journal.BladeName, bladeSpec, orphans); err != nil {
+			return err
+		}
+		if !adopted {
+			return nil
+		}
+		for idx, expStatus := range expStatuses {
+			expStatuses[idx] = scheduleRetry(cb.Spec.Experiments[idx], expStatus, time.Now())
+		}
+		phase := bladePhase(expStatuses)
+		logFields.Infof("adopt the injections in the journal, update phase from %s to %s", cb.Status.Phase, phase)
+		cb.Status.ExpStatuses = expStatuses
+		cb.Status.Phase = phase
+		return r.client.Status().Update(ctx, cb)
+	}
+	// the injections not in the status are never destroyed by the chaosblade
+	recorded := make(map[string]bool)
+	if found {
+		for _, expStatus := range cb.Status.ExpStatuses {
+			for _, rsStatus := range expStatus.ResStatuses {
+				recorded[rsStatus.Id] = true
+			}
+		}
+	}
+	orphans := make([]model.JournalEntry, 0)
+	for _, entry := range journal.Entries {
+		if entry.State == model.JournalInjectedState && !recorded[entry.Id] {
+			orphans = append(orphans, entry)
+		}
+	}
+	return r.destroyInjections(journal.BladeName, bladeSpec, orphans)
+}
+
+// adoptInjections returns the experiment statuses of the injections, and the orphan injections which cannot be
+// attributed to a unique experiment. False is returned if nothing is adopted.
+func adoptInjections(bladeSpec v1alpha1.ChaosBladeSpec, entries []model.JournalEntry) ([]v1alpha1.ExperimentStatus, []model.JournalEntry, bool) {
+	expStatuses := make([]v1alpha1.ExperimentStatus, 0, len(bladeSpec.Experiments))
+	orphans := make([]model.JournalEntry, 0)
+	adopted := false
+	attributed := make([]bool, len(entries))
+	for _, expSpec := range bladeSpec.Experiments {
+		rsStatuses := make([]v1alpha1.ResourceStatus, 0)
+		success := true
+		for i, entry := range entries {
+			if !entry.Matches(expSpec) || matchedExperiments(bladeSpec, entry) > 1 {
+				continue
+			}
+			attributed[i] = true
+			rsStatus := entry.ResourceStatus()
+			if entry.State == model.JournalPendingState {
+				rsStatus.Error = "the injection result is lost when the operator restarted"
+			}
+			success = success && rsStatus.Success
+			adopted = adopted || rsStatus.Success
+			rsStatuses = append(rsStatuses, rsStatus)
+		}
+		expStatus := v1alpha1.CreateSuccessExperimentStatus(rsStatuses)
+		if len(rsStatuses) == 0 {
+			expStatus = v1alpha1.CreateFailExperimentStatus("the experiment was not created before the operator restarted", rsStatuses)
+		} else if !success {
+			expStatus = v1alpha1.CreateFailExperimentStatus("see resStatus for the error details", rsStatuses)
+		}
+		expStatus.Scope = expSpec.Scope
+		expStatus.Target = expSpec.Target
+		expStatus.Action = expSpec.Action
+		expStatus.Name = expSpec.Name
+		expStatus.AppliedSpec = expSpec.DeepCopy()
+		expStatuses = append(expStatuses, expStatus)
+	}
+	for i, entry := range entries {
+		if !attributed[i] && entry.State == model.JournalInjectedState {
+			orphans = append(orphans, entry)
+		}
+	}
+	return expStatuses, orphans, adopted
+}
+
+func matchedExperiments(bladeSpec v1alpha1.ChaosBladeSpec, entry model.JournalEntry) int {
+	count := 0
+	for _, expSpec := range bladeSpec.Experiments {
+		if entry.Matches(expSpec) {
+			count++
+		}
+	}
+	return count
+}
+
+// destroyInjections destroys the injections by the experiment spec of them, the uid is enough for the
+// chaosblade tool to destroy them if the spec is not recorded
+func (r *ReconcileChaosBlade) destroyInjections(bladeName string, bladeSpec *v1alpha1.ChaosBladeSpec, entries []model.JournalEntry) error {
+	for _, entry := range entries {
+		expSpec := v1alpha1.ExperimentSpec{Scope: entry.Scope, Target: entry.Target, Action: entry.Action}
+		if bladeSpec != nil {
+			for _, exp := range bladeSpec.Experiments {
+				if entry.Matches(exp) {
+					expSpec = exp
+					break
+				}
+			}
+		}
+		expStatus := v1alpha1.ExperimentStatus{
+			Name:        entry.Name,
+			Scope:       entry.Scope,
+			Target:      entry.Target,
+			Action:      entry.Action,
+			ResStatuses: []v1alpha1.ResourceStatus{entry.ResourceStatus()},
+		}
+		logrus.WithField("blade", bladeName).WithField("identifier", entry.Identifier).WithField("uid", entry.Id).
+			Infoln("destroy the orphan injection in the journal")
+		if expStatus = r.Executor.Destroy(bladeName, expSpec, expStatus); !expStatus.Success {
+			return fmt.Errorf("destroy the orphan injection %s in %s failed, %s", entry.Id, entry.Identifier, expStatus.Error)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chaosblade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/chaosblade-io/chaosblade-operator/channel"
+	"github.com/chaosblade-io/chaosblade-operator/exec/model"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/apis/chaosblade/v1alpha1"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/runtime/chaosblade"
+)
+
+func Test_adoptInjections(t *testing.T) {
+	bladeSpec := v1alpha1.ChaosBladeSpec{Experiments: []v1alpha1.ExperimentSpec{
+		{Scope: "pod", Target: "cpu", Action: "fullload"},
+		{Scope: "pod", Target: "network", Action: "delay"},
+		{Scope: "pod", Target: "network", Action: "delay"},
+		{Scope: "pod", Target: "mem", Action: "load"},
+	}}
+	entries := []model.JournalEntry{
+		{Scope: "pod", Target: "cpu", Action: "fullload", Identifier: "default/node1/pod1", Id: "a", State: model.JournalInjectedState},
+		{Scope: "pod", Target: "cpu", Action: "fullload", Identifier: "default/node1/pod2", State: model.JournalPendingState},
+		{Scope: "pod", Target: "network", Action: "delay", Identifier: "default/node1/pod1", Id: "b", State: model.JournalInjectedState},
+	}
+	expStatuses, orphans, adopted := adoptInjections(bladeSpec, entries)
+	if !adopted || len(expStatuses) != 4 {
+		t.Fatalf("expected 4 experiment statuses adopted, got %+v", expStatuses)
+	}
+	if cpu := expStatuses[0]; cpu.Success || len(cpu.ResStatuses) != 2 || !cpu.ResStatuses[0].Success || cpu.ResStatuses[1].Success {
+		t.Errorf("expected the cpu experiment is partially adopted, got %+v", cpu)
+	}
+	if mem := expStatuses[3]; mem.Success || len(mem.ResStatuses) != 0 {
+		t.Errorf("expected the mem experiment failed, got %+v", mem)
+	}
+	// the network experiments are ambiguous
+	if len(orphans) != 1 || orphans[0].Id != "b" {
+		t.Errorf("expected the network injection is an orphan, got %+v", orphans)
+	}
+}
+
+func Test_adoptInjectionsByNames(t *testing.T) {
+	bladeSpec := v1alpha1.ChaosBladeSpec{Experiments: []v1alpha1.ExperimentSpec{
+		{Name: "eth0-delay", Scope: "pod", Target: "network", Action: "delay"},
+		{Name: "eth1-delay", Scope: "pod", Target: "network", Action: "delay"},
+	}}
+	entries := []model.JournalEntry{
+		{Name: "eth0-delay", Scope: "pod", Target: "network", Action: "delay", Identifier: "default/node1/pod1", Id: "a", State: model.JournalInjectedState},
+		{Name: "eth1-delay", Scope: "pod", Target: "network", Action: "delay", Identifier: "default/node1/pod1", Id: "b", State: model.JournalInjectedState},
+	}
+	expStatuses, orphans, adopted := adoptInjections(bladeSpec, entries)
+	if !adopted || len(orphans) != 0 {
+		t.Fatalf("expected the injections of the same action adopted by the names, got %+v, orphans %+v", expStatuses, orphans)
+	}
+	for idx, id := range []string{"a", "b"} {
+		if expStatus := expStatuses[idx]; !expStatus.Success || len(expStatus.ResStatuses) != 1 || expStatus.ResStatuses[0].Id != id {
+			t.Errorf("unexpected status of the %s experiment %+v", bladeSpec.Experiments[idx].Name, expStatus)
+		}
+	}
+}
+
+// managerClient reads from the cache like the client of the manager, which fails before the cache is started
+type managerClient struct {
+	client.Client
+	started bool
+}
+
+func (c *managerClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if !c.started {
+		return &cache.ErrCacheNotStarted{}
+	}
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *managerClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if !c.started {
+		return &cache.ErrCacheNotStarted{}
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
+func (c *managerClient) WaitForCacheSync(ctx context.Context) bool {
+	c.started = true
+	return true
+}
+
+func TestStartupRecoverer(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	v1alpha1.SchemeBuilder.AddToScheme(scheme)
+	journalEnable := chaosblade.JournalEnable
+	chaosblade.JournalEnable = true
+	defer func() { chaosblade.JournalEnable = journalEnable }()
+
+	ctx := context.Background()
+	// the status of the legacy chaosblade is written by the older operator
+	legacy := &v1alpha1.ChaosBlade{
+		ObjectMeta: metav1.ObjectMeta{Name: "network-delay"},
+		Spec: v1alpha1.ChaosBladeSpec{Experiments: []v1alpha1.ExperimentSpec{
+			{Scope: "pod", Target: "network", Action: "delay"},
+		}},
+		Status: v1alpha1.ChaosBladeStatus{
+			Phase:       v1alpha1.ClusterPhaseRunning,
+			ExpStatuses: []v1alpha1.ExperimentStatus{{Scope: "pod", Target: "network", Action: "delay", Success: true}},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(legacy).
+		WithStatusSubresource(&v1alpha1.ChaosBlade{}).Build()
+	cb := &v1alpha1.ChaosBlade{
+		ObjectMeta: metav1.ObjectMeta{Name: "cpu-load"},
+		Spec: v1alpha1.ChaosBladeSpec{Experiments: []v1alpha1.ExperimentSpec{
+			{Scope: "pod", Target: "cpu", Action: "fullload"},
+		}},
+	}
+	// the journals are not cached
+	clientset := k8sfake.NewSimpleClientset()
+	if err := model.BeginJournal(ctx, &channel.Client{Interface: clientset}, cb); err != nil {
+		t.Fatalf("begin journal failed, %v", err)
+	}
+	mgrClient := &managerClient{Client: fakeClient}
+	rcb := &ReconcileChaosBlade{client: &channel.Client{Interface: clientset, Client: mgrClient}, recovered: make(chan struct{})}
+
+	// the reconciler waits for the recovery
+	waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	if _, err := rcb.Reconcile(waitCtx, reconcile.Request{NamespacedName: types.NamespacedName{Name: cb.Name}}); err == nil {
+		t.Errorf("expected the reconciler waits for the recovery")
+	}
+
+	if err := (&startupRecoverer{cache: mgrClient, rcb: rcb}).Start(ctx); err != nil {
+		t.Fatalf("recover journals failed, %v", err)
+	}
+	select {
+	case <-rcb.recovered:
+	default:
+		t.Errorf("expected the reconciler released after the recovery")
+	}
+	journals, err := model.ListJournals(ctx, &channel.Client{Interface: clientset})
+	if err != nil {
+		t.Fatalf("list journals failed, %v", err)
+	}
+	if len(journals) != 0 {
+		t.Errorf("expected the journal recovered and committed after the cache started, got %+v", journals)
+	}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: legacy.Name}, legacy); err != nil {
+		t.Fatalf("get the legacy chaosblade failed, %v", err)
+	}
+	setExperimentNames(&legacy.Spec)
+	if legacy.Status.ExpStatuses[0].AppliedSpec == nil || !isSpecApplied(legacy) {
+		t.Errorf("expected the applied spec of the legacy chaosblade migrated, got %+v", legacy.Status)
+	}
+}
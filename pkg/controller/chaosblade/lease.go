@@ -0,0 +1,70 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chaosblade
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/chaosblade-io/chaosblade-operator/channel"
+	"github.com/chaosblade-io/chaosblade-operator/exec/pod"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/apis/chaosblade/v1alpha1"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/runtime/chaosblade"
+)
+
+// startRenewIOFaultLease renews the lease of the pod IO faults periodically, so the faults
+// with ttl live only while the blades exist and the operator is running
+func startRenewIOFaultLease(ctx context.Context, mgr manager.Manager) {
+	if chaosblade.IOFaultLeaseInterval <= 0 {
+		logrus.Infoln("renewing io fault lease is disabled")
+		return
+	}
+	cli := mgr.GetClient().(*channel.Client)
+	ticker := time.NewTicker(chaosblade.IOFaultLeaseInterval)
+	defer ticker.Stop()
+	logrus.Infof("start renewing io fault lease, interval: %s", chaosblade.IOFaultLeaseInterval)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			renewIOFaultLease(ctx, cli)
+		}
+	}
+}
+
+func renewIOFaultLease(ctx context.Context, cli *channel.Client) {
+	results := &v1alpha1.ChaosBladeList{}
+	if err := cli.List(ctx, results, &client.ListOptions{}); err != nil {
+		logrus.Errorf("renew io fault lease, list blade error: %v", err)
+		return
+	}
+	blades := make([]*v1alpha1.ChaosBlade, 0, len(results.Items))
+	for i := range results.Items {
+		blade := &results.Items[i]
+		if blade.DeletionTimestamp != nil || (!isRunningPhase(blade.Status.Phase) &&
+			blade.Status.Phase != v1alpha1.ClusterPhaseUpdating) {
+			continue
+		}
+		blades = append(blades, blade)
+	}
+	pod.RenewIOFaultLeases(ctx, cli, blades)
+}
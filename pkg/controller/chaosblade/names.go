@@ -0,0 +1,102 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chaosblade
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chaosblade-io/chaosblade-operator/pkg/apis/chaosblade/v1alpha1"
+)
+
+const InvalidSpecReason = "InvalidSpec"
+
+// setExperimentNames names the experiments without names by the target and the action, such as network-delay
+// and network-delay-2. It only migrates the statuses of the chaosblades created before the names are required.
+func setExperimentNames(bladeSpec *v1alpha1.ChaosBladeSpec) {
+	used := make(map[string]bool, len(bladeSpec.Experiments))
+	for _, expSpec := range bladeSpec.Experiments {
+		used[expSpec.Name] = true
+	}
+	for idx := range bladeSpec.Experiments {
+		expSpec := &bladeSpec.Experiments[idx]
+		if expSpec.Name != "" {
+			continue
+		}
+		base := strings.ToLower(fmt.Sprintf("%s-%s", expSpec.Target, expSpec.Action))
+		name := base
+		for i := 2; used[name]; i++ {
+			name = fmt.Sprintf("%s-%d", base, i)
+		}
+		expSpec.Name = name
+		used[name] = true
+	}
+}
+
+// nameLegacyExperiments names the experiments without names in memory by the names migrated to the statuses,
+// the spec is not written. The experiments of the new chaosblades are not named, they are rejected by the
+// validation since the names are required.
+func nameLegacyExperiments(cb *v1alpha1.ChaosBlade) {
+	migrated := make(map[string]bool, len(cb.Status.ExpStatuses))
+	for _, expStatus := range cb.Status.ExpStatuses {
+		if expStatus.Name != "" {
+			migrated[expStatus.Name] = true
+		}
+	}
+	if len(migrated) == 0 {
+		return
+	}
+	named := v1alpha1.ChaosBladeSpec{Experiments: make([]v1alpha1.ExperimentSpec, len(cb.Spec.Experiments))}
+	for idx, expSpec := range cb.Spec.Experiments {
+		named.Experiments[idx] = v1alpha1.ExperimentSpec{Name: expSpec.Name, Target: expSpec.Target, Action: expSpec.Action}
+	}
+	setExperimentNames(&named)
+	for idx := range cb.Spec.Experiments {
+		expSpec := &cb.Spec.Experiments[idx]
+		if expSpec.Name == "" && migrated[named.Experiments[idx].Name] {
+			expSpec.Name = named.Experiments[idx].Name
+		}
+	}
+}
+
+// validateExperimentNames returns an error if the experiment names are empty or duplicated
+func validateExperimentNames(bladeSpec v1alpha1.ChaosBladeSpec) error {
+	names := make(map[string]bool, len(bladeSpec.Experiments))
+	for _, expSpec := range bladeSpec.Experiments {
+		if expSpec.Name == "" {
+			return fmt.Errorf("the name of the %s %s experiment is empty", expSpec.Target, expSpec.Action)
+		}
+		if names[expSpec.Name] {
+			return fmt.Errorf("the experiment name %s is duplicated", expSpec.Name)
+		}
+		names[expSpec.Name] = true
+	}
+	return nil
+}
+
+// validateExperiments returns an error if the experiments cannot be applied
+func validateExperiments(bladeSpec v1alpha1.ChaosBladeSpec) error {
+	if err := validateExperimentNames(bladeSpec); err != nil {
+		return err
+	}
+	for _, expSpec := range bladeSpec.Experiments {
+		if err := validateRamp(expSpec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chaosblade
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/chaosblade-io/chaosblade-operator/channel"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/apis/chaosblade/v1alpha1"
+)
+
+func Test_setExperimentNames(t *testing.T) {
+	bladeSpec := v1alpha1.ChaosBladeSpec{Experiments: []v1alpha1.ExperimentSpec{
+		newExperimentSpec("network", "delay"),
+		{Name: "network-delay", Scope: "pod", Target: "network", Action: "loss"},
+		newExperimentSpec("network", "delay"),
+	}}
+	setExperimentNames(&bladeSpec)
+	names := []string{bladeSpec.Experiments[0].Name, bladeSpec.Experiments[1].Name, bladeSpec.Experiments[2].Name}
+	if !reflect.DeepEqual(names, []string{"network-delay-2", "network-delay", "network-delay-3"}) {
+		t.Errorf("unexpected names %v", names)
+	}
+	setExperimentNames(&bladeSpec)
+	if renamed := []string{bladeSpec.Experiments[0].Name, bladeSpec.Experiments[1].Name, bladeSpec.Experiments[2].Name}; !reflect.DeepEqual(renamed, names) {
+		t.Errorf("the named experiments must not be renamed, got %v", renamed)
+	}
+	if err := validateExperimentNames(bladeSpec); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	bladeSpec.Experiments[2].Name = "network-delay"
+	if err := validateExperimentNames(bladeSpec); err == nil {
+		t.Errorf("the duplicated names must be invalid")
+	}
+}
+
+func Test_diffExperimentsByName(t *testing.T) {
+	cpu := newExperimentSpec("cpu", "fullload")
+	cpu.Name = "cpu"
+	delay := newExperimentSpec("network", "delay", v1alpha1.FlagSpec{Name: "time", Value: []string{"100"}})
+	delay.Name = "delay"
+	slower := *delay.DeepCopy()
+	slower.Matchers[0].Value = []string{"200"}
+	// renamed experiment is another experiment even if it is identical
+	renamed := *cpu.DeepCopy()
+	renamed.Name = "cpu-2"
+
+	diff := diffExperiments([]v1alpha1.ExperimentSpec{cpu, delay}, []v1alpha1.ExperimentSpec{slower, renamed, cpu})
+	if !reflect.DeepEqual(diff.oldIndexes, []int{1, -1, 0}) {
+		t.Errorf("unexpected paired old experiments %v", diff.oldIndexes)
+	}
+	if !reflect.DeepEqual(diff.changed, []bool{true, false, false}) {
+		t.Errorf("unexpected changed experiments %v", diff.changed)
+	}
+	if len(diff.removed) != 0 {
+		t.Errorf("unexpected removed experiments %v", diff.removed)
+	}
+}
+
+func Test_nameLegacyExperiments(t *testing.T) {
+	named := newExperimentSpec("cpu", "fullload")
+	named.Name = "cpu"
+	cb := &v1alpha1.ChaosBlade{
+		Spec: v1alpha1.ChaosBladeSpec{Experiments: []v1alpha1.ExperimentSpec{
+			named, newExperimentSpec("network", "delay"), newExperimentSpec("network", "delay"),
+		}},
+		// the second network delay is added after the migration
+		Status: v1alpha1.ChaosBladeStatus{ExpStatuses: []v1alpha1.ExperimentStatus{
+			{Name: "cpu"}, {Name: "network-delay"},
+		}},
+	}
+	nameLegacyExperiments(cb)
+	names := []string{cb.Spec.Experiments[0].Name, cb.Spec.Experiments[1].Name, cb.Spec.Experiments[2].Name}
+	if !reflect.DeepEqual(names, []string{"cpu", "network-delay", ""}) {
+		t.Errorf("unexpected names %v", names)
+	}
+
+	// the experiments of the new chaosblades are not named
+	cb = &v1alpha1.ChaosBlade{Spec: v1alpha1.ChaosBladeSpec{Experiments: []v1alpha1.ExperimentSpec{
+		newExperimentSpec("network", "delay"),
+	}}}
+	nameLegacyExperiments(cb)
+	if name := cb.Spec.Experiments[0].Name; name != "" {
+		t.Errorf("the experiment of the new chaosblade must not be named, got %s", name)
+	}
+}
+
+func TestReconcileWithoutNamingSpec(t *testing.T) {
+	scheme := runtime.NewScheme()
+	v1alpha1.SchemeBuilder.AddToScheme(scheme)
+	expSpec := newExperimentSpec("network", "delay")
+	appliedSpec := expSpec.DeepCopy()
+	appliedSpec.Name = "network-delay"
+	unnamed := &v1alpha1.ChaosBlade{
+		ObjectMeta: metav1.ObjectMeta{Name: "delay"},
+		Spec:       v1alpha1.ChaosBladeSpec{Experiments: []v1alpha1.ExperimentSpec{expSpec}},
+	}
+	legacy := &v1alpha1.ChaosBlade{
+		ObjectMeta: metav1.ObjectMeta{Name: "legacy", Finalizers: []string{chaosbladeFinalizer}},
+		Spec:       v1alpha1.ChaosBladeSpec{Experiments: []v1alpha1.ExperimentSpec{expSpec}},
+		Status: v1alpha1.ChaosBladeStatus{
+			Phase: v1alpha1.ClusterPhaseRunning,
+			ExpStatuses: []v1alpha1.ExperimentStatus{{
+				Name: "network-delay", Scope: "pod", Target: "network", Action: "delay",
+				State: v1alpha1.SuccessState, Success: true, AppliedSpec: appliedSpec,
+			}},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(unnamed, legacy).
+		WithStatusSubresource(&v1alpha1.ChaosBlade{}).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &ReconcileChaosBlade{client: &channel.Client{Client: fakeClient}, recorder: recorder}
+	ctx := context.Background()
+
+	// the new chaosblade without the names is rejected
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: unnamed.Name}}
+	if _, err := r.Reconcile(ctx, request); err != nil {
+		t.Fatalf("reconcile failed, %v", err)
+	}
+	if err := fakeClient.Get(ctx, request.NamespacedName, unnamed); err != nil {
+		t.Fatalf("get chaosblade failed, %v", err)
+	}
+	if unnamed.Status.Phase != v1alpha1.ClusterPhaseInitial || contains(unnamed.GetFinalizers(), chaosbladeFinalizer) {
+		t.Errorf("expected the chaosblade not applied, got %s, %v", unnamed.Status.Phase, unnamed.GetFinalizers())
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, InvalidSpecReason) {
+			t.Errorf("unexpected event %s", event)
+		}
+	default:
+		t.Errorf("expected the invalid spec event")
+	}
+
+	// the legacy chaosblade is named by the migrated status, the spec is not written
+	request = reconcile.Request{NamespacedName: types.NamespacedName{Name: legacy.Name}}
+	if _, err := r.Reconcile(ctx, request); err != nil {
+		t.Fatalf("reconcile failed, %v", err)
+	}
+	select {
+	case event := <-recorder.Events:
+		t.Errorf("unexpected event %s", event)
+	default:
+	}
+	if err := fakeClient.Get(ctx, request.NamespacedName, legacy); err != nil {
+		t.Fatalf("get chaosblade failed, %v", err)
+	}
+	if legacy.Status.Phase != v1alpha1.ClusterPhaseRunning {
+		t.Errorf("expected the legacy chaosblade running, got %s", legacy.Status.Phase)
+	}
+	if name := legacy.Spec.Experiments[0].Name; name != "" {
+		t.Errorf("the generated name %s must not be written to the spec", name)
+	}
+}
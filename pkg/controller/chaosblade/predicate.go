@@ -17,7 +17,6 @@
 package chaosblade
 
 import (
-	"encoding/json"
 	"reflect"
 
 	"github.com/sirupsen/logrus"
@@ -42,6 +41,10 @@ func (sup *SpecUpdatedPredicateForRunningPhase) Create(e event.TypedCreateEvent[
 	if obj.Status.Phase == v1alpha1.ClusterPhaseInitial {
 		return true
 	}
+	// retry the failed targets and continue the ramps after the operator restarted
+	if hasPendingInjection(obj) {
+		return true
+	}
 	logrus.Infof("unexpected phase for cb creating, name: %s, phase: %s", obj.Name, obj.Status.Phase)
 	return false
 }
@@ -68,13 +71,8 @@ func (*SpecUpdatedPredicateForRunningPhase) Update(e event.TypedUpdateEvent[*v1a
 	}
 	logrus.Debugf("updating oldObj: %+v", oldObj)
 	logrus.Debugf("updating newObj: %+v", newObj)
+	// the spec change is applied by the diff with the applied spec in the status
 	if !reflect.DeepEqual(newObj.Spec, oldObj.Spec) {
-		bytes, err := json.Marshal(oldObj.Spec.DeepCopy())
-		if err != nil {
-			logrus.Warningf("marshal old spec failed, %+v", err)
-			return false
-		}
-		newObj.SetAnnotations(map[string]string{"preSpec": string(bytes)})
 		return true
 	}
 
@@ -86,7 +84,7 @@ func (*SpecUpdatedPredicateForRunningPhase) Update(e event.TypedUpdateEvent[*v1a
 		newObj.GetDeletionTimestamp() != nil {
 		return true
 	}
-	if newObj.Status.Phase == v1alpha1.ClusterPhaseRunning ||
+	if isRunningPhase(newObj.Status.Phase) ||
 		newObj.Status.Phase == v1alpha1.ClusterPhaseError ||
 		newObj.Status.Phase == v1alpha1.ClusterPhaseDestroying {
 		return false
@@ -0,0 +1,51 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chaosblade
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/chaosblade-io/chaosblade-operator/pkg/apis/chaosblade/v1alpha1"
+)
+
+func TestSpecUpdatedPredicateForRunningPhase_Update(t *testing.T) {
+	oldObj := &v1alpha1.ChaosBlade{
+		ObjectMeta: metav1.ObjectMeta{Name: "delay", Annotations: map[string]string{"owner": "sre"}},
+		Spec: v1alpha1.ChaosBladeSpec{Experiments: []v1alpha1.ExperimentSpec{
+			newExperimentSpec("network", "delay", v1alpha1.FlagSpec{Name: "time", Value: []string{"100"}}),
+		}},
+		Status: v1alpha1.ChaosBladeStatus{Phase: v1alpha1.ClusterPhaseRunning},
+	}
+	newObj := oldObj.DeepCopy()
+	newObj.Spec.Experiments[0].Matchers[0].Value = []string{"200"}
+	expected := newObj.DeepCopy()
+
+	p := &SpecUpdatedPredicateForRunningPhase{}
+	if !p.Update(event.TypedUpdateEvent[*v1alpha1.ChaosBlade]{ObjectOld: oldObj, ObjectNew: newObj}) {
+		t.Errorf("the spec update must be reconciled")
+	}
+	if !reflect.DeepEqual(newObj, expected) {
+		t.Errorf("the predicate must not change the object, got %+v", newObj.ObjectMeta)
+	}
+	if p.Update(event.TypedUpdateEvent[*v1alpha1.ChaosBlade]{ObjectOld: newObj, ObjectNew: newObj.DeepCopy()}) {
+		t.Errorf("the running chaosblade without spec changes must not be reconciled")
+	}
+}
@@ -0,0 +1,217 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chaosblade
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/chaosblade-io/chaosblade-operator/exec/model"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/apis/chaosblade/v1alpha1"
+)
+
+// validateRamp returns an error if the ramp steps of the experiment are illegal. The ramp injects into the
+// targets selected at the first step, so the sticky experiments and the experiments selecting the targets by
+// group are not supported.
+func validateRamp(expSpec v1alpha1.ExperimentSpec) error {
+	if len(expSpec.Ramp) == 0 {
+		return nil
+	}
+	flags := model.ExtractExpModelFromExperimentSpec(expSpec).ActionFlags
+	if flags[model.ResourceGroupKeyFlag.Name] != "" {
+		return fmt.Errorf("the ramp of the %s experiment does not support the %s flag", expSpec.Name,
+			model.ResourceGroupKeyFlag.Name)
+	}
+	if isStickyExperiment(expSpec, flags) {
+		return fmt.Errorf("the ramp of the %s experiment does not support the %s flag", expSpec.Name,
+			model.StickyFlag.Name)
+	}
+	for idx, step := range expSpec.Ramp {
+		if step.Count < 0 || step.Percent < 0 || (step.Count > 0) == (step.Percent > 0) {
+			return fmt.Errorf("the step %d of the ramp of the %s experiment must have either the count or the percent",
+				idx, expSpec.Name)
+		}
+		if step.Percent > 100 {
+			return fmt.Errorf("the percent of the step %d of the ramp of the %s experiment must be in (0, 100]",
+				idx, expSpec.Name)
+		}
+		if step.Hold.Duration < 0 {
+			return fmt.Errorf("the hold of the step %d of the ramp of the %s experiment is negative", idx, expSpec.Name)
+		}
+	}
+	return nil
+}
+
+// rampStepTargets returns the number of the targets injected by the end of the step
+func rampStepTargets(step v1alpha1.RampStep, total int) int {
+	count := int(step.Count)
+	if count == 0 {
+		count = (total*int(step.Percent) + 99) / 100
+	}
+	if count > total {
+		return total
+	}
+	return count
+}
+
+// rampInjected returns the number of the targets injected by the executed steps
+func rampInjected(ramp *v1alpha1.RampStatus) int {
+	if len(ramp.Steps) == 0 {
+		return 0
+	}
+	return int(ramp.Steps[len(ramp.Steps)-1].Injected)
+}
+
+// isRampStepDue returns true if the next step of the ramp experiment is up
+func isRampStepDue(expStatus v1alpha1.ExperimentStatus, now time.Time) bool {
+	return expStatus.Ramp != nil && expStatus.Ramp.NextStepTime != nil && !expStatus.Ramp.NextStepTime.After(now)
+}
+
+// finishRampStep records the result of the step, the next step is scheduled after the hold of the step if the
+// injection is successful and more targets are left, otherwise the ramp is completed or stopped
+func finishRampStep(expSpec v1alpha1.ExperimentSpec, expStatus v1alpha1.ExperimentStatus, success bool, stepError string,
+	injected int, now time.Time,
+) v1alpha1.ExperimentStatus {
+	ramp := expStatus.Ramp.DeepCopy()
+	ramp.Steps = append(ramp.Steps, v1alpha1.RampStepStatus{
+		Injected: int32(injected),
+		Success:  success,
+		Error:    stepError,
+		Time:     metav1.NewTime(now),
+	})
+	ramp.NextStepTime = nil
+	switch {
+	case !success:
+		ramp.State = v1alpha1.RampStoppedState
+	case len(ramp.Steps) >= len(expSpec.Ramp) || injected >= len(ramp.Targets):
+		ramp.State = v1alpha1.RampCompletedState
+	default:
+		ramp.State = v1alpha1.RampRunningState
+		next := metav1.NewTime(now.Add(expSpec.Ramp[len(ramp.Steps)-1].Hold.Duration))
+		ramp.NextStepTime = &next
+	}
+	expStatus.Ramp = ramp
+	return expStatus
+}
+
+// createExperiments creates the experiments concurrently and returns the statuses in the order of the specs.
+// The ramp experiments select the targets first and inject into the targets of the first step only.
+func (r *ReconcileChaosBlade) createExperiments(reqLogger *logrus.Entry, cb *v1alpha1.ChaosBlade,
+	expSpecs []v1alpha1.ExperimentSpec, parallelism int, startBarrier bool,
+) []v1alpha1.ExperimentStatus {
+	now := time.Now()
+	expStatuses := make([]v1alpha1.ExperimentStatus, len(expSpecs))
+	ramps := make([]*v1alpha1.RampStatus, len(expSpecs))
+	createIndexes := make([]int, 0, len(expSpecs))
+	createSpecs := make([]v1alpha1.ExperimentSpec, 0, len(expSpecs))
+	for idx, expSpec := range expSpecs {
+		if len(expSpec.Ramp) == 0 {
+			createIndexes = append(createIndexes, idx)
+			createSpecs = append(createSpecs, expSpec)
+			continue
+		}
+		stepSpec, ramp, err := r.startRamp(cb, expSpec, now)
+		if err != nil {
+			reqLogger.WithField("experiment", expSpec.Name).WithError(err).Errorln("select the targets of the ramp failed")
+			expStatus := v1alpha1.CreateFailExperimentStatus(err.Error(), []v1alpha1.ResourceStatus{})
+			expStatus.Name = expSpec.Name
+			expStatus.Scope = expSpec.Scope
+			expStatus.Target = expSpec.Target
+			expStatus.Action = expSpec.Action
+			expStatus.AppliedSpec = expSpec.DeepCopy()
+			expStatuses[idx] = expStatus
+			continue
+		}
+		ramps[idx] = ramp
+		createIndexes = append(createIndexes, idx)
+		createSpecs = append(createSpecs, stepSpec)
+	}
+	created := r.Executor.CreateConcurrently(cb.Name, createSpecs, parallelism, startBarrier)
+	for i, idx := range createIndexes {
+		expStatus := created[i]
+		if ramps[idx] != nil {
+			// the status records the spec of the ramp instead of the first step
+			expStatus.AppliedSpec = expSpecs[idx].DeepCopy()
+			expStatus.Ramp = ramps[idx]
+			expStatus = finishRampStep(expSpecs[idx], expStatus, created[i].Success, created[i].Error,
+				rampStepTargets(expSpecs[idx].Ramp[0], len(ramps[idx].Targets)), now)
+		}
+		expStatuses[idx] = expStatus
+	}
+	return expStatuses
+}
+
+// startRamp selects the targets of the ramp experiment and returns the spec injecting into the targets of the
+// first step
+func (r *ReconcileChaosBlade) startRamp(cb *v1alpha1.ChaosBlade, expSpec v1alpha1.ExperimentSpec, now time.Time) (
+	v1alpha1.ExperimentSpec, *v1alpha1.RampStatus, error,
+) {
+	remaining, err := remainingTimeout(cb, model.ExtractExpModelFromExperimentSpec(expSpec).ActionFlags, now)
+	if err != nil {
+		return expSpec, nil, err
+	}
+	targets, err := r.Executor.SelectTargets(cb.Name, expSpec)
+	if err != nil {
+		return expSpec, nil, err
+	}
+	if len(targets) == 0 {
+		return expSpec, nil, fmt.Errorf("no targets are selected by the %s experiment", expSpec.Name)
+	}
+	ramp := &v1alpha1.RampStatus{State: v1alpha1.RampRunningState, Targets: targets}
+	return namedSpec(expSpec, targets[:rampStepTargets(expSpec.Ramp[0], len(targets))], remaining), ramp, nil
+}
+
+// rampStep injects the experiment into the targets of the next step of the ramp, the ramp is stopped if the
+// injection failed or the experiment timed out
+func (r *ReconcileChaosBlade) rampStep(reqLogger *logrus.Entry, cb *v1alpha1.ChaosBlade, expSpec v1alpha1.ExperimentSpec,
+	expStatus v1alpha1.ExperimentStatus, now time.Time,
+) v1alpha1.ExperimentStatus {
+	ramp := expStatus.Ramp
+	injected := rampInjected(ramp)
+	step := len(ramp.Steps)
+	if step >= len(expSpec.Ramp) {
+		// the steps are removed from the spec
+		expStatus.Ramp = ramp.DeepCopy()
+		expStatus.Ramp.State = v1alpha1.RampCompletedState
+		expStatus.Ramp.NextStepTime = nil
+		return expStatus
+	}
+	flags := model.ExtractExpModelFromExperimentSpec(expSpec).ActionFlags
+	remaining, err := remainingTimeout(cb, flags, now)
+	if err != nil {
+		return finishRampStep(expSpec, expStatus, false, err.Error(), injected, now)
+	}
+	if remaining <= 0 {
+		return finishRampStep(expSpec, expStatus, false, "the experiment timed out", injected, now)
+	}
+	policy, resp := model.GetSuccessPolicy(flags)
+	if !resp.Success {
+		return finishRampStep(expSpec, expStatus, false, resp.Err, injected, now)
+	}
+	next := rampStepTargets(expSpec.Ramp[step], len(ramp.Targets))
+	if next <= injected {
+		return finishRampStep(expSpec, expStatus, true, "", injected, now)
+	}
+	names := ramp.Targets[injected:next]
+	reqLogger.Infof("inject the experiment into the targets %v of the ramp step %d", names, step)
+	newExpStatus := r.Executor.Create(cb.Name, namedSpec(expSpec, names, remaining))
+	expStatus = policy.Apply(mergeRetriedStatus(expSpec.Scope, expStatus, names, newExpStatus.ResStatuses))
+	return finishRampStep(expSpec, expStatus, newExpStatus.Success, newExpStatus.Error, next, now)
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chaosblade
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/chaosblade-io/chaosblade-operator/exec/model"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/apis/chaosblade/v1alpha1"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/runtime/chaosblade"
+)
+
+// fakeExecutor injects the experiments into the pods selected by names, the pods in failed are failed.
+// The destroy fails if destroyFailed is true.
+type fakeExecutor struct {
+	targets       []string
+	failed        map[string]bool
+	created       [][]string
+	destroyFailed bool
+}
+
+func (*fakeExecutor) Name() string {
+	return "fake"
+}
+
+func (e *fakeExecutor) Create(bladeName string, expSpec v1alpha1.ExperimentSpec) v1alpha1.ExperimentStatus {
+	var names []string
+	for _, matcher := range expSpec.Matchers {
+		if matcher.Name == model.ResourceNamesFlag.Name {
+			names = matcher.Value
+		}
+	}
+	e.created = append(e.created, names)
+	statuses := make([]v1alpha1.ResourceStatus, 0, len(names))
+	success := true
+	for _, name := range names {
+		statuses = append(statuses, v1alpha1.ResourceStatus{Identifier: "default/node1/" + name, Success: !e.failed[name]})
+		success = success && !e.failed[name]
+	}
+	if !success {
+		return setStatusSpec(v1alpha1.CreateFailExperimentStatus("see resStatus for the error details", statuses), expSpec)
+	}
+	return setStatusSpec(v1alpha1.CreateSuccessExperimentStatus(statuses), expSpec)
+}
+
+func (e *fakeExecutor) CreateConcurrently(bladeName string, expSpecs []v1alpha1.ExperimentSpec, parallelism int,
+	startBarrier bool,
+) []v1alpha1.ExperimentStatus {
+	statuses := make([]v1alpha1.ExperimentStatus, 0, len(expSpecs))
+	for _, expSpec := range expSpecs {
+		statuses = append(statuses, e.Create(bladeName, expSpec))
+	}
+	return statuses
+}
+
+func (e *fakeExecutor) Destroy(bladeName string, expSpec v1alpha1.ExperimentSpec, oldExpStatus v1alpha1.ExperimentStatus) v1alpha1.ExperimentStatus {
+	if e.destroyFailed {
+		statuses := make([]v1alpha1.ResourceStatus, 0, len(oldExpStatus.ResStatuses))
+		for _, rsStatus := range oldExpStatus.ResStatuses {
+			rsStatus.Success = false
+			statuses = append(statuses, rsStatus)
+		}
+		return v1alpha1.CreateFailExperimentStatus("see resStatus for the error details", statuses)
+	}
+	return v1alpha1.CreateDestroyedExperimentStatus(oldExpStatus.ResStatuses)
+}
+
+func (e *fakeExecutor) SelectTargets(bladeName string, expSpec v1alpha1.ExperimentSpec) ([]string, error) {
+	return e.targets, nil
+}
+
+func (*fakeExecutor) Update(bladeName string, oldExpSpec, expSpec v1alpha1.ExperimentSpec, oldExpStatus v1alpha1.ExperimentStatus) (v1alpha1.ExperimentStatus, bool) {
+	return oldExpStatus, false
+}
+
+func setStatusSpec(expStatus v1alpha1.ExperimentStatus, expSpec v1alpha1.ExperimentSpec) v1alpha1.ExperimentStatus {
+	expStatus.Name = expSpec.Name
+	expStatus.AppliedSpec = expSpec.DeepCopy()
+	return expStatus
+}
+
+func Test_validateRamp(t *testing.T) {
+	tests := []struct {
+		name    string
+		ramp    []v1alpha1.RampStep
+		flags   []v1alpha1.FlagSpec
+		wantErr bool
+	}{
+		{name: "count and percent", ramp: []v1alpha1.RampStep{{Count: 1}, {Percent: 50}}},
+		{name: "both", ramp: []v1alpha1.RampStep{{Count: 1, Percent: 50}}, wantErr: true},
+		{name: "none", ramp: []v1alpha1.RampStep{{}}, wantErr: true},
+		{name: "percent", ramp: []v1alpha1.RampStep{{Percent: 101}}, wantErr: true},
+		{name: "hold", ramp: []v1alpha1.RampStep{{Count: 1, Hold: metav1.Duration{Duration: -time.Second}}}, wantErr: true},
+		{
+			name:    "group",
+			ramp:    []v1alpha1.RampStep{{Count: 1}},
+			flags:   []v1alpha1.FlagSpec{{Name: model.ResourceGroupKeyFlag.Name, Value: []string{"app"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expSpec := newExperimentSpec("cpu", "fullload", tt.flags...)
+			expSpec.Ramp = tt.ramp
+			if err := validateRamp(expSpec); (err != nil) != tt.wantErr {
+				t.Errorf("validateRamp() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	if got := rampStepTargets(v1alpha1.RampStep{Percent: 10}, 25); got != 3 {
+		t.Errorf("rampStepTargets() = %d, want 3", got)
+	}
+	if got := rampStepTargets(v1alpha1.RampStep{Count: 40}, 25); got != 25 {
+		t.Errorf("rampStepTargets() = %d, want 25", got)
+	}
+}
+
+func Test_rampStep(t *testing.T) {
+	defer func(policy string) { chaosblade.SuccessPolicy = policy }(chaosblade.SuccessPolicy)
+	chaosblade.SuccessPolicy = model.SuccessPolicyAll
+
+	executor := &fakeExecutor{targets: []string{"web-0", "web-1", "web-2", "web-3"}, failed: map[string]bool{"web-2": true}}
+	r := &ReconcileChaosBlade{Executor: executor}
+	cb := &v1alpha1.ChaosBlade{ObjectMeta: metav1.ObjectMeta{Name: "ramp", CreationTimestamp: metav1.Now()}}
+	expSpec := newExperimentSpec("cpu", "fullload")
+	expSpec.Name = "cpu"
+	expSpec.Ramp = []v1alpha1.RampStep{
+		{Count: 1, Hold: metav1.Duration{Duration: time.Minute}},
+		{Percent: 50},
+		{Percent: 100},
+	}
+	cb.Spec.Experiments = []v1alpha1.ExperimentSpec{expSpec}
+	reqLogger := logrus.WithField("test", t.Name())
+
+	cb.Status.ExpStatuses = r.createExperiments(reqLogger, cb, cb.Spec.Experiments, 1, false)
+	expStatus := cb.Status.ExpStatuses[0]
+	if !expStatus.Success || len(expStatus.ResStatuses) != 1 || len(expStatus.AppliedSpec.Ramp) != 3 {
+		t.Fatalf("unexpected status of the first step %+v", expStatus)
+	}
+	if expStatus.Ramp.State != v1alpha1.RampRunningState || expStatus.Ramp.NextStepTime == nil || !hasPendingInjection(cb) {
+		t.Fatalf("unexpected ramp of the first step %+v", expStatus.Ramp)
+	}
+	if isRampStepDue(expStatus, time.Now()) {
+		t.Errorf("the next step must wait for the hold")
+	}
+
+	now := time.Now().Add(time.Minute)
+	expStatus = r.rampStep(reqLogger, cb, expSpec, expStatus, now)
+	if !expStatus.Success || len(expStatus.ResStatuses) != 2 || expStatus.Ramp.Steps[1].Injected != 2 ||
+		expStatus.Ramp.NextStepTime == nil {
+		t.Fatalf("unexpected status of the second step %+v", expStatus)
+	}
+	expStatus = r.rampStep(reqLogger, cb, expSpec, expStatus, now)
+	if expStatus.Success || expStatus.Ramp.State != v1alpha1.RampStoppedState || expStatus.Ramp.NextStepTime != nil {
+		t.Fatalf("the ramp must be stopped by the failed step, %+v", expStatus.Ramp)
+	}
+	if names := retryTargets(expSpec, expStatus); names != nil {
+		t.Errorf("the stopped ramp must not be retried, got %v", names)
+	}
+	want := [][]string{{"web-0"}, {"web-1"}, {"web-2", "web-3"}}
+	if !reflect.DeepEqual(executor.created, want) {
+		t.Errorf("unexpected injections %v, want %v", executor.created, want)
+	}
+}
@@ -0,0 +1,281 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chaosblade
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/chaosblade-io/chaosblade-operator/exec/model"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/apis/chaosblade/v1alpha1"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/runtime/chaosblade"
+)
+
+// maxRetryFailedBackoff is the max wait between the retries of the failed targets
+const maxRetryFailedBackoff = 10 * time.Minute
+
+// isRunningPhase returns true if the experiments are running in all or some of the targets
+func isRunningPhase(phase v1alpha1.ClusterPhase) bool {
+	return phase == v1alpha1.ClusterPhaseRunning || phase == v1alpha1.ClusterPhasePartiallyRunning
+}
+
+// bladePhase returns Running if all experiments are successful in all targets, PartiallyRunning if some
+// experiments or targets failed, and Error if no experiment is successful
+func bladePhase(expStatuses []v1alpha1.ExperimentStatus) v1alpha1.ClusterPhase {
+	phase := v1alpha1.ClusterPhaseError
+	complete := true
+	for _, expStatus := range expStatuses {
+		if !expStatus.Success {
+			complete = false
+			continue
+		}
+		phase = v1alpha1.ClusterPhaseRunning
+		for _, rsStatus := range expStatus.ResStatuses {
+			complete = complete && rsStatus.Success
+		}
+	}
+	if phase == v1alpha1.ClusterPhaseRunning && !complete {
+		return v1alpha1.ClusterPhasePartiallyRunning
+	}
+	return phase
+}
+
+// hasPendingInjection returns true if any experiment of the chaosblade will be injected again into the failed
+// targets or into the targets of the next ramp step
+func hasPendingInjection(cb *v1alpha1.ChaosBlade) bool {
+	for _, expStatus := range cb.Status.ExpStatuses {
+		if nextInjectionTime(expStatus) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// nextInjectionTime returns the earlier time of the next retry and the next ramp step of the experiment
+func nextInjectionTime(expStatus v1alpha1.ExperimentStatus) *metav1.Time {
+	next := expStatus.NextRetryTime
+	if expStatus.Ramp != nil && expStatus.Ramp.NextStepTime != nil &&
+		(next == nil || expStatus.Ramp.NextStepTime.Before(next)) {
+		next = expStatus.Ramp.NextStepTime
+	}
+	return next
+}
+
+// nextRetryAfter returns the wait before the earliest retry or ramp step of the chaosblade, false is returned
+// if none
+func nextRetryAfter(cb *v1alpha1.ChaosBlade, now time.Time) (time.Duration, bool) {
+	var after time.Duration
+	found := false
+	for _, expStatus := range cb.Status.ExpStatuses {
+		next := nextInjectionTime(expStatus)
+		if next == nil {
+			continue
+		}
+		wait := next.Sub(now)
+		if wait < 0 {
+			wait = 0
+		}
+		if !found || wait < after {
+			after = wait
+			found = true
+		}
+	}
+	return after, found
+}
+
+// retryResult requeues the chaosblade at the earliest retry or ramp step
+func retryResult(cb *v1alpha1.ChaosBlade) reconcile.Result {
+	after, found := nextRetryAfter(cb, time.Now())
+	if !found {
+		return reconcile.Result{}
+	}
+	// the zero RequeueAfter does not requeue
+	return reconcile.Result{RequeueAfter: after + time.Millisecond}
+}
+
+// retryTargets returns the names of the pods or the nodes of the failed targets. The sticky experiments inject
+// into the failed targets by themselves, and the experiments selecting the targets by group or with the stopped
+// ramp are not retried.
+func retryTargets(expSpec v1alpha1.ExperimentSpec, expStatus v1alpha1.ExperimentStatus) []string {
+	flags := model.ExtractExpModelFromExperimentSpec(expSpec).ActionFlags
+	if flags[model.ResourceGroupKeyFlag.Name] != "" || (chaosblade.StickyEnable && isStickyExperiment(expSpec, flags)) {
+		return nil
+	}
+	if expStatus.Ramp != nil && expStatus.Ramp.State == v1alpha1.RampStoppedState {
+		return nil
+	}
+	names := make([]string, 0)
+	added := make(map[string]bool)
+	for _, rsStatus := range expStatus.ResStatuses {
+		if rsStatus.Success {
+			continue
+		}
+		name := retryTargetName(expSpec.Scope, rsStatus)
+		if name == "" || added[name] {
+			continue
+		}
+		added[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+func retryTargetName(scope string, rsStatus v1alpha1.ResourceStatus) string {
+	meta := model.ParseIdentifier(rsStatus.Identifier)
+	if scope == v1alpha1.NodeKind {
+		return meta.NodeName
+	}
+	return meta.PodName
+}
+
+// scheduleRetry sets the next retry time of the experiment with the failed targets, the wait is doubled after
+// each retry
+func scheduleRetry(expSpec v1alpha1.ExperimentSpec, expStatus v1alpha1.ExperimentStatus, now time.Time) v1alpha1.ExperimentStatus {
+	expStatus.NextRetryTime = nil
+	if int(expStatus.Retries) >= chaosblade.RetryFailedTargets || len(retryTargets(expSpec, expStatus)) == 0 {
+		return expStatus
+	}
+	backoff := chaosblade.RetryFailedBackoff
+	for i := int32(0); i < expStatus.Retries && backoff < maxRetryFailedBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxRetryFailedBackoff {
+		backoff = maxRetryFailedBackoff
+	}
+	next := metav1.NewTime(now.Add(backoff))
+	expStatus.NextRetryTime = &next
+	return expStatus
+}
+
+// mergeRetriedStatus replaces the failed targets injected again by the results of the retry
+func mergeRetriedStatus(scope string, expStatus v1alpha1.ExperimentStatus, names []string, retried []v1alpha1.ResourceStatus) v1alpha1.ExperimentStatus {
+	retriedNames := make(map[string]bool, len(names))
+	for _, name := range names {
+		retriedNames[name] = true
+	}
+	statuses := make([]v1alpha1.ResourceStatus, 0, len(expStatus.ResStatuses)+len(retried))
+	for _, rsStatus := range expStatus.ResStatuses {
+		if !rsStatus.Success && retriedNames[retryTargetName(scope, rsStatus)] {
+			continue
+		}
+		statuses = append(statuses, rsStatus)
+	}
+	expStatus.ResStatuses = append(statuses, retried...)
+	return expStatus
+}
+
+// injectPendingTargets injects the experiments into the targets of the ramp steps and again into the failed
+// targets when the time is up, and updates the phase of the chaosblade by the results
+func (r *ReconcileChaosBlade) injectPendingTargets(ctx context.Context, reqLogger *logrus.Entry, cb *v1alpha1.ChaosBlade) (reconcile.Result, error) {
+	if !isSpecApplied(cb) {
+		return reconcile.Result{}, nil
+	}
+	now := time.Now()
+	retried := make(map[int]v1alpha1.ExperimentStatus)
+	for idx, expSpec := range cb.Spec.Experiments {
+		expStatus := cb.Status.ExpStatuses[idx]
+		logFields := reqLogger.WithField("experiment", idx)
+		if isRampStepDue(expStatus, now) {
+			retried[idx] = r.rampStep(logFields, cb, expSpec, expStatus, now)
+			continue
+		}
+		if expStatus.NextRetryTime == nil || expStatus.NextRetryTime.After(now) {
+			continue
+		}
+		flags := model.ExtractExpModelFromExperimentSpec(expSpec).ActionFlags
+		names := retryTargets(expSpec, expStatus)
+		remaining, err := remainingTimeout(cb, flags, now)
+		if err != nil || remaining <= 0 || len(names) == 0 {
+			expStatus.NextRetryTime = nil
+			retried[idx] = expStatus
+			continue
+		}
+		policy, resp := model.GetSuccessPolicy(flags)
+		if !resp.Success {
+			expStatus.NextRetryTime = nil
+			retried[idx] = expStatus
+			continue
+		}
+		logFields.Infof("inject the experiment again into the failed targets %v, retries %d", names, expStatus.Retries+1)
+		newExpStatus := r.Executor.Create(cb.Name, namedSpec(expSpec, names, remaining))
+		expStatus = policy.Apply(mergeRetriedStatus(expSpec.Scope, expStatus, names, newExpStatus.ResStatuses))
+		expStatus.Retries++
+		retried[idx] = scheduleRetry(expSpec, expStatus, time.Now())
+	}
+	if len(retried) == 0 {
+		return retryResult(cb), nil
+	}
+	var latest *v1alpha1.ChaosBlade
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest = &v1alpha1.ChaosBlade{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: cb.Name}, latest); err != nil {
+			return err
+		}
+		nameLegacyExperiments(latest)
+		if latest.GetDeletionTimestamp() != nil || latest.Status.Phase != cb.Status.Phase || !isSpecApplied(latest) {
+			return errRetryPhaseChanged
+		}
+		for idx, expStatus := range retried {
+			latest.Status.ExpStatuses[idx] = expStatus
+		}
+		latest.Status.Phase = bladePhase(latest.Status.ExpStatuses)
+		return r.client.Status().Update(ctx, latest)
+	})
+	if err == errRetryPhaseChanged || apierrors.IsNotFound(err) {
+		// the injections of the retry or the ramp step are not recorded by the chaosblade, so destroy them
+		for idx, expStatus := range retried {
+			r.Executor.Destroy(cb.Name, cb.Spec.Experiments[idx], retriedTargets(cb.Status.ExpStatuses[idx], expStatus))
+		}
+		return reconcile.Result{}, nil
+	}
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := model.CommitJournal(ctx, r.client, cb.Name); err != nil {
+		reqLogger.WithError(err).Warnln("delete the injection journal failed")
+	}
+	reqLogger.Infof("inject the pending targets, update phase from %s to %s", cb.Status.Phase, latest.Status.Phase)
+	return retryResult(latest), nil
+}
+
+var errRetryPhaseChanged = errors.New("the chaosblade phase is changed during the retry")
+
+// retriedTargets returns the experiment status with the successful targets added by the retry
+func retriedTargets(oldExpStatus, expStatus v1alpha1.ExperimentStatus) v1alpha1.ExperimentStatus {
+	existing := make(map[string]bool, len(oldExpStatus.ResStatuses))
+	for _, rsStatus := range oldExpStatus.ResStatuses {
+		if rsStatus.Success {
+			existing[rsStatus.Identifier+"/"+rsStatus.Id] = true
+		}
+	}
+	added := make([]v1alpha1.ResourceStatus, 0)
+	for _, rsStatus := range expStatus.ResStatuses {
+		if rsStatus.Success && !existing[rsStatus.Identifier+"/"+rsStatus.Id] {
+			added = append(added, rsStatus)
+		}
+	}
+	expStatus.ResStatuses = added
+	return expStatus
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chaosblade
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-operator/pkg/apis/chaosblade/v1alpha1"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/runtime/chaosblade"
+)
+
+func Test_bladePhase(t *testing.T) {
+	succeeded := v1alpha1.ExperimentStatus{Success: true, ResStatuses: []v1alpha1.ResourceStatus{{Success: true}}}
+	partial := v1alpha1.ExperimentStatus{Success: true, ResStatuses: []v1alpha1.ResourceStatus{{Success: true}, {Success: false}}}
+	failed := v1alpha1.ExperimentStatus{Success: false}
+	tests := []struct {
+		name        string
+		expStatuses []v1alpha1.ExperimentStatus
+		want        v1alpha1.ClusterPhase
+	}{
+		{"all succeeded", []v1alpha1.ExperimentStatus{succeeded, succeeded}, v1alpha1.ClusterPhaseRunning},
+		{"target failed", []v1alpha1.ExperimentStatus{succeeded, partial}, v1alpha1.ClusterPhasePartiallyRunning},
+		{"experiment failed", []v1alpha1.ExperimentStatus{succeeded, failed}, v1alpha1.ClusterPhasePartiallyRunning},
+		{"all failed", []v1alpha1.ExperimentStatus{failed, failed}, v1alpha1.ClusterPhaseError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bladePhase(tt.expStatuses); got != tt.want {
+				t.Errorf("bladePhase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_retryFailedTargets(t *testing.T) {
+	defer func(attempts int, backoff time.Duration) {
+		chaosblade.RetryFailedTargets, chaosblade.RetryFailedBackoff = attempts, backoff
+	}(chaosblade.RetryFailedTargets, chaosblade.RetryFailedBackoff)
+	chaosblade.RetryFailedTargets, chaosblade.RetryFailedBackoff = 3, 30*time.Second
+
+	expSpec := v1alpha1.ExperimentSpec{Scope: "container", Target: "cpu", Action: "fullload"}
+	expStatus := v1alpha1.ExperimentStatus{ResStatuses: []v1alpha1.ResourceStatus{
+		{Identifier: "default/node1/web-0/nginx/c0/containerd", Success: true},
+		{Identifier: "default/node1/web-1/nginx/c1/containerd", Success: false},
+		{Identifier: "default/node2/web-2/nginx/c2/containerd", Success: false},
+	}}
+	names := retryTargets(expSpec, expStatus)
+	if !reflect.DeepEqual(names, []string{"web-1", "web-2"}) {
+		t.Fatalf("unexpected retry targets %v", names)
+	}
+
+	now := time.Now()
+	expStatus.Retries = 2
+	scheduled := scheduleRetry(expSpec, expStatus, now)
+	if scheduled.NextRetryTime == nil || !scheduled.NextRetryTime.Time.Equal(now.Add(2*time.Minute)) {
+		t.Errorf("unexpected next retry time %v", scheduled.NextRetryTime)
+	}
+	expStatus.Retries = 3
+	if scheduled := scheduleRetry(expSpec, expStatus, now); scheduled.NextRetryTime != nil {
+		t.Errorf("the retries are exhausted, but the next retry time is %v", scheduled.NextRetryTime)
+	}
+
+	merged := mergeRetriedStatus(expSpec.Scope, expStatus, names, []v1alpha1.ResourceStatus{
+		{Identifier: "default/node1/web-1/nginx/c1/containerd", Success: true},
+	})
+	if len(merged.ResStatuses) != 2 || !merged.ResStatuses[0].Success || !merged.ResStatuses[1].Success {
+		t.Errorf("unexpected merged statuses %+v", merged.ResStatuses)
+	}
+}
@@ -0,0 +1,377 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chaosblade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	pkglabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/chaosblade-io/chaosblade-operator/channel"
+	"github.com/chaosblade-io/chaosblade-operator/exec/model"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/apis/chaosblade/v1alpha1"
+)
+
+// stickyRequeueInterval is the interval to check the sticky experiments again if the targets are not enough,
+// such as the new replicas are not running yet
+const stickyRequeueInterval = 30 * time.Second
+
+// ReconcileStickyExperiments injects the sticky experiments of the running chaosblades into the new pods
+// matching the selector, and drops the targets which no longer exist
+type ReconcileStickyExperiments struct {
+	client   *channel.Client
+	Executor model.ExpController
+}
+
+// addStickyController watches the pods and reconciles the chaosblades with the sticky experiments in the
+// namespaces of them. It is separated from the chaosblade controller, so the pod events never trigger the
+// phase transitions.
+func addStickyController(mgr manager.Manager, rcb *ReconcileChaosBlade) error {
+	c, err := controller.New("chaosblade-sticky-controller", mgr, controller.Options{
+		Reconciler: &ReconcileStickyExperiments{client: rcb.client, Executor: rcb.Executor},
+	})
+	if err != nil {
+		return err
+	}
+	cache := mgr.GetCache()
+	return c.Watch(source.Kind(
+		cache,
+		&corev1.Pod{},
+		handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, pod *corev1.Pod) []reconcile.Request {
+			blades := &v1alpha1.ChaosBladeList{}
+			if err := cache.List(ctx, blades); err != nil {
+				logrus.WithError(err).Warnln("list chaosblades for the sticky experiments failed")
+				return nil
+			}
+			requests := make([]reconcile.Request, 0)
+			for _, blade := range blades.Items {
+				if isRunningPhase(blade.Status.Phase) && hasStickyExperiment(&blade, pod.Namespace) {
+					requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: blade.Name}})
+				}
+			}
+			return requests
+		}),
+		predicate.TypedFuncs[*corev1.Pod]{
+			CreateFunc: func(e event.TypedCreateEvent[*corev1.Pod]) bool {
+				return e.Object.Status.Phase == corev1.PodRunning
+			},
+			UpdateFunc: func(e event.TypedUpdateEvent[*corev1.Pod]) bool {
+				return e.ObjectOld.Status.Phase != corev1.PodRunning && e.ObjectNew.Status.Phase == corev1.PodRunning
+			},
+			DeleteFunc: func(e event.TypedDeleteEvent[*corev1.Pod]) bool {
+				return true
+			},
+			GenericFunc: func(e event.TypedGenericEvent[*corev1.Pod]) bool {
+				return false
+			},
+		},
+	))
+}
+
+// hasStickyExperiment returns true if the chaosblade has a sticky experiment selecting the pods in the namespace
+func hasStickyExperiment(cb *v1alpha1.ChaosBlade, namespace string) bool {
+	for _, expSpec := range cb.Spec.Experiments {
+		flags := model.ExtractExpModelFromExperimentSpec(expSpec).ActionFlags
+		if isStickyExperiment(expSpec, flags) && stickyNamespace(flags) == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// isStickyExperiment returns true if the experiment is sticky and can be injected into the new pods. The pod
+// delete experiment is never sticky, otherwise all replicas are deleted repeatedly.
+func isStickyExperiment(expSpec v1alpha1.ExperimentSpec, flags map[string]string) bool {
+	if flags[model.StickyFlag.Name] != "true" ||
+		(expSpec.Scope != v1alpha1.PodKind && expSpec.Scope != v1alpha1.ContainerKind) {
+		return false
+	}
+	return !(expSpec.Target == "pod" && expSpec.Action == "delete")
+}
+
+func stickyNamespace(flags map[string]string) string {
+	if namespace := flags[model.ResourceNamespaceFlag.Name]; namespace != "" {
+		return namespace
+	}
+	return model.DefaultNamespace
+}
+
+func (r *ReconcileStickyExperiments) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := logrus.WithField("Request.Name", request.Name).WithField("controller", "sticky")
+	cb := &v1alpha1.ChaosBlade{}
+	if err := r.client.Get(ctx, request.NamespacedName, cb); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	nameLegacyExperiments(cb)
+	if cb.GetDeletionTimestamp() != nil || !isRunningPhase(cb.Status.Phase) || !isSpecApplied(cb) {
+		return reconcile.Result{}, nil
+	}
+	result := reconcile.Result{}
+	for idx, expSpec := range cb.Spec.Experiments {
+		expModel := model.ExtractExpModelFromExperimentSpec(expSpec)
+		if !isStickyExperiment(expSpec, expModel.ActionFlags) {
+			continue
+		}
+		logFields := reqLogger.WithField("experiment", idx)
+		added, dropped, satisfied, err := r.stick(ctx, logFields, cb, expSpec, expModel.ActionFlags, cb.Status.ExpStatuses[idx])
+		if err != nil {
+			logFields.WithError(err).Warnln("reconcile the sticky experiment failed")
+		}
+		if !satisfied {
+			result.RequeueAfter = stickyRequeueInterval
+		}
+		if len(added) == 0 && len(dropped) == 0 {
+			continue
+		}
+		if err := r.updateStickyStatus(ctx, cb.Name, idx, expSpec, added, dropped); err != nil {
+			return result, err
+		}
+		logFields.Infof("the sticky experiment is updated, %d targets added, %d targets dropped", len(added), len(dropped))
+	}
+	return result, nil
+}
+
+// stick injects the experiment into the new matching pods, and returns the statuses of them and the identifiers
+// of the targets which no longer exist. False is returned if the targets are not enough.
+func (r *ReconcileStickyExperiments) stick(ctx context.Context, logFields *logrus.Entry, cb *v1alpha1.ChaosBlade,
+	expSpec v1alpha1.ExperimentSpec, flags map[string]string, expStatus v1alpha1.ExperimentStatus,
+) ([]v1alpha1.ResourceStatus, map[string]bool, bool, error) {
+	if flags[model.ResourceGroupKeyFlag.Name] != "" {
+		logFields.Warnf("the %s flag is not supported by the sticky experiment", model.ResourceGroupKeyFlag.Name)
+		return nil, nil, true, nil
+	}
+	remaining, err := remainingTimeout(cb, flags, time.Now())
+	if err != nil || remaining <= 0 {
+		return nil, nil, true, err
+	}
+	pods, err := r.listStickyPods(ctx, flags)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	desired, resp := model.GetResourceCount(len(pods), flags)
+	if !resp.Success {
+		return nil, nil, true, fmt.Errorf("%s", resp.Err)
+	}
+	picked, dropped := planStickyTargets(pods, expStatus.ResStatuses, desired)
+	if len(picked) == 0 {
+		return nil, dropped, true, nil
+	}
+	logFields.Infof("inject the sticky experiment into the new pods %v, remaining %s", picked, remaining)
+	newExpStatus := r.Executor.Create(cb.Name, namedSpec(expSpec, picked, remaining))
+	satisfied := newExpStatus.Success && len(newExpStatus.ResStatuses) == len(picked)
+	return newExpStatus.ResStatuses, dropped, satisfied, nil
+}
+
+// listStickyPods returns the running pods matching the namespace, the labels and the names of the experiment
+func (r *ReconcileStickyExperiments) listStickyPods(ctx context.Context, flags map[string]string) ([]corev1.Pod, error) {
+	namespace := stickyNamespace(flags)
+	requirements := model.ParseLabels(flags[model.ResourceLabelsFlag.Name])
+	pods := make([]corev1.Pod, 0)
+	if names := flags[model.ResourceNamesFlag.Name]; names != "" {
+		for _, name := range strings.Split(names, ",") {
+			pod := corev1.Pod{}
+			if err := r.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &pod); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+			if len(requirements) == 0 || model.MapContains(pod.Labels, requirements) {
+				pods = append(pods, pod)
+			}
+		}
+	} else {
+		podList := &corev1.PodList{}
+		if err := r.client.List(ctx, podList, client.InNamespace(namespace),
+			client.MatchingLabelsSelector{Selector: pkglabels.NewSelector().Add(requirements...)}); err != nil {
+			return nil, err
+		}
+		pods = podList.Items
+	}
+	running := make([]corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if pod.DeletionTimestamp == nil && pod.Status.Phase == corev1.PodRunning {
+			running = append(running, pod)
+		}
+	}
+	sort.Slice(running, func(i, j int) bool { return running[i].Name < running[j].Name })
+	return running, nil
+}
+
+// planStickyTargets returns the new pods to inject to keep the desired targets, and the identifiers of the
+// targets which no longer exist. The pods with a failed target are injected again.
+func planStickyTargets(pods []corev1.Pod, statuses []v1alpha1.ResourceStatus, desired int) ([]string, map[string]bool) {
+	podsByName := make(map[string]*corev1.Pod, len(pods))
+	for i := range pods {
+		podsByName[pods[i].Name] = &pods[i]
+	}
+	dropped := make(map[string]bool)
+	targeted := make(map[string]bool)
+	for _, status := range statuses {
+		meta := model.ParseIdentifier(status.Identifier)
+		pod := podsByName[meta.PodName]
+		if pod == nil || pod.Spec.NodeName != meta.NodeName || !hasContainer(pod, meta.ContainerId) {
+			dropped[status.Identifier] = true
+			continue
+		}
+		if status.Success {
+			targeted[pod.Name] = true
+		}
+	}
+	picked := make([]string, 0)
+	for _, pod := range pods {
+		if len(targeted)+len(picked) >= desired {
+			break
+		}
+		if !targeted[pod.Name] {
+			picked = append(picked, pod.Name)
+		}
+	}
+	return picked, dropped
+}
+
+// hasContainer returns true if the container id is empty or belongs to the pod, the container id changes if
+// the pod is recreated with the same name
+func hasContainer(pod *corev1.Pod, containerId string) bool {
+	if containerId == "" {
+		return true
+	}
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		id := containerStatus.ContainerID
+		if i := strings.Index(id, "://"); i >= 0 {
+			id = id[i+len("://"):]
+		}
+		// the docker container id is truncated in the identifier
+		if strings.HasPrefix(id, containerId) {
+			return true
+		}
+	}
+	return false
+}
+
+// remainingTimeout returns the remaining duration of the experiment since the chaosblade was created, the
+// max duration is returned if the experiment has no timeout
+func remainingTimeout(cb *v1alpha1.ChaosBlade, flags map[string]string, now time.Time) (time.Duration, error) {
+	timeoutValue := flags["timeout"]
+	if timeoutValue == "" {
+		return math.MaxInt64, nil
+	}
+	timeout, err := time.ParseDuration(timeoutValue)
+	if err != nil {
+		seconds, parseErr := strconv.ParseUint(timeoutValue, 10, 64)
+		if parseErr != nil {
+			return 0, fmt.Errorf("illegal timeout %s, %v", timeoutValue, err)
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+	return cb.CreationTimestamp.Add(timeout).Sub(now), nil
+}
+
+// namedSpec returns the experiment spec selecting the pods or the nodes by names with the remaining timeout
+func namedSpec(expSpec v1alpha1.ExperimentSpec, names []string, remaining time.Duration) v1alpha1.ExperimentSpec {
+	newSpec := *expSpec.DeepCopy()
+	matchers := make([]v1alpha1.FlagSpec, 0, len(newSpec.Matchers)+1)
+	for _, matcher := range newSpec.Matchers {
+		switch matcher.Name {
+		case model.ResourceNamesFlag.Name, model.ResourceCountFlag.Name, model.ResourcePercentFlag.Name:
+			continue
+		case "timeout":
+			if remaining != math.MaxInt64 {
+				matcher.Value = []string{strconv.FormatInt(int64(math.Ceil(remaining.Seconds())), 10)}
+			}
+		}
+		matchers = append(matchers, matcher)
+	}
+	newSpec.Matchers = append(matchers, v1alpha1.FlagSpec{Name: model.ResourceNamesFlag.Name, Value: names})
+	return newSpec
+}
+
+// updateStickyStatus merges the targets to the latest status of the chaosblade. The injections are destroyed
+// if the chaosblade is not running anymore.
+func (r *ReconcileStickyExperiments) updateStickyStatus(ctx context.Context, bladeName string, idx int,
+	expSpec v1alpha1.ExperimentSpec, added []v1alpha1.ResourceStatus, dropped map[string]bool,
+) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cb := &v1alpha1.ChaosBlade{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: bladeName}, cb); err != nil {
+			return err
+		}
+		nameLegacyExperiments(cb)
+		if cb.GetDeletionTimestamp() != nil || !isRunningPhase(cb.Status.Phase) || !isSpecApplied(cb) ||
+			len(cb.Status.ExpStatuses) <= idx {
+			return errStickyNotRunning
+		}
+		cb.Status.ExpStatuses[idx] = mergeStickyStatus(cb.Status.ExpStatuses[idx], added, dropped)
+		return r.client.Status().Update(ctx, cb)
+	})
+	if err == errStickyNotRunning || apierrors.IsNotFound(err) {
+		r.Executor.Destroy(bladeName, expSpec, v1alpha1.ExperimentStatus{
+			Scope:       expSpec.Scope,
+			Target:      expSpec.Target,
+			Action:      expSpec.Action,
+			ResStatuses: added,
+		})
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := model.CommitJournal(ctx, r.client, bladeName); err != nil {
+		logrus.WithField("Request.Name", bladeName).WithError(err).Warnln("delete the injection journal failed")
+	}
+	return nil
+}
+
+var errStickyNotRunning = errors.New("the chaosblade is not running")
+
+// mergeStickyStatus drops the targets not existing and the failed targets injected again, and appends the new
+// targets
+func mergeStickyStatus(expStatus v1alpha1.ExperimentStatus, added []v1alpha1.ResourceStatus, dropped map[string]bool) v1alpha1.ExperimentStatus {
+	addedPods := make(map[string]bool, len(added))
+	for _, status := range added {
+		addedPods[model.ParseIdentifier(status.Identifier).PodName] = true
+	}
+	statuses := make([]v1alpha1.ResourceStatus, 0, len(expStatus.ResStatuses)+len(added))
+	for _, status := range expStatus.ResStatuses {
+		if dropped[status.Identifier] || (!status.Success && addedPods[model.ParseIdentifier(status.Identifier).PodName]) {
+			continue
+		}
+		statuses = append(statuses, status)
+	}
+	expStatus.ResStatuses = append(statuses, added...)
+	return expStatus
+}
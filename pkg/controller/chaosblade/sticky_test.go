@@ -0,0 +1,97 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chaosblade
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/chaosblade-io/chaosblade-operator/pkg/apis/chaosblade/v1alpha1"
+)
+
+func newRunningPod(name, node, containerId string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       corev1.PodSpec{NodeName: node},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{ContainerID: "containerd://" + containerId}},
+		},
+	}
+}
+
+func Test_planStickyTargets(t *testing.T) {
+	pods := []corev1.Pod{
+		newRunningPod("web-0", "node1", "c0"),
+		newRunningPod("web-1", "node2", "d1"),
+		newRunningPod("web-2", "node1", "c2"),
+		newRunningPod("web-3", "node3", "c3"),
+	}
+	statuses := []v1alpha1.ResourceStatus{
+		{Identifier: "default/node1/web-0/nginx/c0/containerd", Success: true},
+		// recreated with the same name
+		{Identifier: "default/node2/web-1/nginx/c1/containerd", Success: true},
+		// rescheduled
+		{Identifier: "default/node9/web-9/nginx/c9/containerd", Success: true},
+		{Identifier: "default/node1/web-2/nginx/c2/containerd", Success: false},
+	}
+	picked, dropped := planStickyTargets(pods, statuses, 3)
+	if !reflect.DeepEqual(picked, []string{"web-1", "web-2"}) {
+		t.Errorf("unexpected picked pods %v", picked)
+	}
+	if len(dropped) != 2 || !dropped[statuses[1].Identifier] || !dropped[statuses[2].Identifier] {
+		t.Errorf("unexpected dropped targets %v", dropped)
+	}
+
+	merged := mergeStickyStatus(v1alpha1.ExperimentStatus{ResStatuses: statuses}, []v1alpha1.ResourceStatus{
+		{Identifier: "default/node2/web-1/nginx/d1/containerd", Success: true},
+		{Identifier: "default/node1/web-2/nginx/c2/containerd", Success: true},
+	}, dropped)
+	if len(merged.ResStatuses) != 3 || merged.ResStatuses[0].Identifier != statuses[0].Identifier {
+		t.Errorf("unexpected merged statuses %+v", merged.ResStatuses)
+	}
+}
+
+func Test_namedSpec(t *testing.T) {
+	cb := &v1alpha1.ChaosBlade{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Minute))}}
+	flags := map[string]string{"timeout": "100"}
+	remaining, err := remainingTimeout(cb, flags, time.Now())
+	if err != nil || remaining <= 30*time.Second || remaining > 40*time.Second {
+		t.Fatalf("unexpected remaining %s, %v", remaining, err)
+	}
+	expSpec := v1alpha1.ExperimentSpec{Scope: "pod", Target: "cpu", Action: "fullload", Matchers: []v1alpha1.FlagSpec{
+		{Name: "labels", Value: []string{"app=web"}},
+		{Name: "evict-count", Value: []string{"2"}},
+		{Name: "timeout", Value: []string{"100"}},
+	}}
+	newSpec := namedSpec(expSpec, []string{"web-1"}, 40*time.Second)
+	want := []v1alpha1.FlagSpec{
+		{Name: "labels", Value: []string{"app=web"}},
+		{Name: "timeout", Value: []string{"40"}},
+		{Name: "names", Value: []string{"web-1"}},
+	}
+	if !reflect.DeepEqual(newSpec.Matchers, want) {
+		t.Errorf("unexpected matchers %+v", newSpec.Matchers)
+	}
+	if len(expSpec.Matchers) != 3 || expSpec.Matchers[2].Value[0] != "100" {
+		t.Errorf("expected the original spec not changed, got %+v", expSpec.Matchers)
+	}
+}
@@ -0,0 +1,189 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chaosblade
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/chaosblade-io/chaosblade-operator/channel"
+	"github.com/chaosblade-io/chaosblade-operator/exec/model"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/apis/chaosblade/v1alpha1"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/runtime/chaosblade"
+)
+
+const (
+	OrphanDestroyedReason     = "OrphanExperimentDestroyed"
+	OrphanDestroyFailedReason = "OrphanExperimentDestroyFailed"
+)
+
+// orphanExperiments counts the orphan experiments found in the chaosblade tool pods by the result of destroying
+var orphanExperiments = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "chaosblade_orphan_experiments_total",
+	Help: "The experiments unknown by the chaosblades found in the chaosblade tool pods",
+}, []string{"node", "target", "result"})
+
+func init() {
+	metrics.Registry.MustRegister(orphanExperiments)
+}
+
+// startSweepOrphanExperiments destroys the experiments in the chaosblade tool pods which are not recorded in
+// any chaosblade periodically, they are left by the force deleted chaosblades or the lost status
+func startSweepOrphanExperiments(ctx context.Context, mgr manager.Manager) {
+	if !chaosblade.DaemonsetEnable || chaosblade.OrphanSweepInterval <= 0 {
+		logrus.Infoln("sweeping orphan experiments is disabled")
+		return
+	}
+	cli := mgr.GetClient().(*channel.Client)
+	recorder := mgr.GetEventRecorderFor("chaosblade-operator")
+	ticker := time.NewTicker(chaosblade.OrphanSweepInterval)
+	defer ticker.Stop()
+	logrus.Infof("start sweeping orphan experiments, interval: %s, grace period: %s",
+		chaosblade.OrphanSweepInterval, chaosblade.OrphanGracePeriod)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sweepOrphanExperiments(ctx, cli, recorder)
+		}
+	}
+}
+
+func sweepOrphanExperiments(ctx context.Context, cli *channel.Client, recorder record.EventRecorder) {
+	known, err := knownExperiments(ctx, cli)
+	if err != nil {
+		logrus.WithError(err).Errorln("sweep orphan experiments, list the known experiments error")
+		return
+	}
+	pods := &corev1.PodList{}
+	if err := cli.List(ctx, pods, client.InNamespace(chaosblade.DaemonsetPodNamespace),
+		client.MatchingLabels(chaosblade.DaemonsetPodLabels)); err != nil {
+		logrus.WithError(err).Errorln("sweep orphan experiments, list the chaosblade tool pods error")
+		return
+	}
+	model.ParallelizeExec(len(pods.Items), func(i int) {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodRunning {
+			return
+		}
+		sweepOrphanExperimentsInPod(ctx, cli, recorder, pod, known)
+	})
+}
+
+// knownExperiments returns the uids of the experiments in the chaosblade statuses and in the journals
+func knownExperiments(ctx context.Context, cli *channel.Client) (map[string]bool, error) {
+	blades := &v1alpha1.ChaosBladeList{}
+	if err := cli.List(ctx, blades); err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool)
+	for _, blade := range blades.Items {
+		for _, expStatus := range blade.Status.ExpStatuses {
+			for _, rsStatus := range expStatus.ResStatuses {
+				if rsStatus.Id != "" {
+					known[rsStatus.Id] = true
+				}
+			}
+		}
+	}
+	if !chaosblade.JournalEnable {
+		return known, nil
+	}
+	journals, err := model.ListJournals(ctx, cli)
+	if err != nil {
+		return nil, err
+	}
+	for _, journal := range journals {
+		for _, entry := range journal.Entries {
+			if entry.Id != "" {
+				known[entry.Id] = true
+			}
+		}
+	}
+	return known, nil
+}
+
+func sweepOrphanExperimentsInPod(ctx context.Context, cli *channel.Client, recorder record.EventRecorder,
+	pod *corev1.Pod, known map[string]bool,
+) {
+	logFields := logrus.WithField("pod", pod.Name).WithField("node", pod.Spec.NodeName)
+	result, err := cli.ExecContext(ctx, &channel.ExecOptions{
+		PodName:       pod.Name,
+		PodNamespace:  pod.Namespace,
+		ContainerName: chaosblade.DaemonsetPodName,
+		Command:       []string{chaosblade.OperatorChaosBladeBlade, "status", "--type", "create"},
+	})
+	var statuses []model.BladeStatus
+	response := model.ParseExecResult(result, err, func(output []byte) *spec.Response {
+		var resp *spec.Response
+		statuses, resp = model.ParseStatusListResponse(output)
+		return resp
+	})
+	if !response.Success {
+		logFields.Warnf("sweep orphan experiments, query the experiments error, %s", response.Err)
+		return
+	}
+	for _, orphan := range findOrphanExperiments(statuses, known, time.Now(), chaosblade.OrphanGracePeriod) {
+		experiment := fmt.Sprintf("%s %s %s", orphan.Command, orphan.SubCommand, orphan.Flag)
+		result, err := cli.ExecContext(ctx, &channel.ExecOptions{
+			PodName:       pod.Name,
+			PodNamespace:  pod.Namespace,
+			ContainerName: chaosblade.DaemonsetPodName,
+			Command:       []string{chaosblade.OperatorChaosBladeBlade, "destroy", orphan.Uid},
+		})
+		resp := model.ParseExecResult(result, err, model.ParseDestroyResponse)
+		if !resp.Success {
+			logFields.WithField("uid", orphan.Uid).Warnf("destroy the orphan experiment error, %s", resp.Err)
+			orphanExperiments.WithLabelValues(pod.Spec.NodeName, orphan.Command, "failed").Inc()
+			recorder.Eventf(pod, corev1.EventTypeWarning, OrphanDestroyFailedReason,
+				"Destroy the orphan experiment %s created at %s failed: %s, %s", orphan.Uid, orphan.CreateTime, experiment, resp.Err)
+			continue
+		}
+		logFields.WithField("uid", orphan.Uid).Infof("the orphan experiment is destroyed, %s", experiment)
+		orphanExperiments.WithLabelValues(pod.Spec.NodeName, orphan.Command, "destroyed").Inc()
+		recorder.Eventf(pod, corev1.EventTypeNormal, OrphanDestroyedReason,
+			"Destroyed the orphan experiment %s created at %s: %s", orphan.Uid, orphan.CreateTime, experiment)
+	}
+}
+
+// findOrphanExperiments returns the running experiments unknown by the chaosblades and older than the grace
+// period, the experiments with an illegal create time are skipped
+func findOrphanExperiments(statuses []model.BladeStatus, known map[string]bool, now time.Time, grace time.Duration) []model.BladeStatus {
+	orphans := make([]model.BladeStatus, 0)
+	for _, status := range statuses {
+		if known[status.Uid] || (status.Status != v1alpha1.SuccessState && status.Status != "Created") {
+			continue
+		}
+		createTime, err := time.Parse(time.RFC3339Nano, status.CreateTime)
+		if err != nil || now.Sub(createTime) < grace {
+			continue
+		}
+		orphans = append(orphans, status)
+	}
+	return orphans
+}
@@ -0,0 +1,40 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chaosblade
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-operator/exec/model"
+)
+
+func Test_findOrphanExperiments(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-time.Hour).Format(time.RFC3339Nano)
+	statuses := []model.BladeStatus{
+		{Uid: "known", Status: "Success", CreateTime: old},
+		{Uid: "orphan", Status: "Success", CreateTime: old},
+		{Uid: "new", Status: "Success", CreateTime: now.Add(-time.Minute).Format(time.RFC3339Nano)},
+		{Uid: "destroyed", Status: "Destroyed", CreateTime: old},
+		{Uid: "illegal", Status: "Success", CreateTime: "yesterday"},
+	}
+	orphans := findOrphanExperiments(statuses, map[string]bool{"known": true}, now, 30*time.Minute)
+	if len(orphans) != 1 || orphans[0].Uid != "orphan" {
+		t.Errorf("expected the orphan experiment found, got %+v", orphans)
+	}
+}
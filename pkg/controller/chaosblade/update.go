@@ -0,0 +1,309 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chaosblade
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/chaosblade-io/chaosblade-operator/exec/model"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/apis/chaosblade/v1alpha1"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/runtime/chaosblade"
+)
+
+// experimentDiff pairs the new experiments with the old ones
+type experimentDiff struct {
+	// oldIndexes is the index of the old experiment paired with each new experiment, -1 if it is added
+	oldIndexes []int
+	// changed is true if the new experiment is different from the paired old one
+	changed []bool
+	// removed is the indexes of the old experiments not paired
+	removed []int
+}
+
+// diffExperiments pairs the experiments with the same names. The old experiments without names, applied by the
+// older operator, are paired with the identical experiments first, and then the changed ones with the same scope,
+// target and action in order.
+func diffExperiments(oldExps, newExps []v1alpha1.ExperimentSpec) experimentDiff {
+	diff := experimentDiff{
+		oldIndexes: make([]int, len(newExps)),
+		changed:    make([]bool, len(newExps)),
+		removed:    make([]int, 0),
+	}
+	paired := make([]bool, len(oldExps))
+	for j, newExp := range newExps {
+		diff.oldIndexes[j] = -1
+		for i, oldExp := range oldExps {
+			if !paired[i] && oldExp.Name != "" && oldExp.Name == newExp.Name {
+				diff.oldIndexes[j] = i
+				diff.changed[j] = !isSameAction(oldExp, newExp) || len(model.ChangedFlags(oldExp, newExp)) != 0
+				paired[i] = true
+				break
+			}
+		}
+	}
+	pairUnnamed := func(changed bool) {
+		for j, newExp := range newExps {
+			if diff.oldIndexes[j] >= 0 {
+				continue
+			}
+			for i, oldExp := range oldExps {
+				if paired[i] || oldExp.Name != "" || !isSameAction(oldExp, newExp) ||
+					(!changed && len(model.ChangedFlags(oldExp, newExp)) != 0) {
+					continue
+				}
+				diff.oldIndexes[j] = i
+				diff.changed[j] = changed
+				paired[i] = true
+				break
+			}
+		}
+	}
+	pairUnnamed(false)
+	pairUnnamed(true)
+	for i := range oldExps {
+		if !paired[i] {
+			diff.removed = append(diff.removed, i)
+		}
+	}
+	return diff
+}
+
+func isSameAction(oldExp, newExp v1alpha1.ExperimentSpec) bool {
+	return oldExp.Scope == newExp.Scope && oldExp.Target == newExp.Target && oldExp.Action == newExp.Action
+}
+
+// appliedExperiments returns the experiment specs applied to the targets by the order of the statuses. The status
+// without the snapshot, which is not migrated on startup, has the scope, the target and the action only, so the
+// experiment is applied again. The names are the ones in the statuses.
+func appliedExperiments(cb *v1alpha1.ChaosBlade) []v1alpha1.ExperimentSpec {
+	experiments := make([]v1alpha1.ExperimentSpec, 0, len(cb.Status.ExpStatuses))
+	for _, expStatus := range cb.Status.ExpStatuses {
+		expSpec := v1alpha1.ExperimentSpec{
+			Scope:  expStatus.Scope,
+			Target: expStatus.Target,
+			Action: expStatus.Action,
+		}
+		if expStatus.AppliedSpec != nil {
+			expSpec = *expStatus.AppliedSpec
+		}
+		// the status written by the older operator has no name
+		expSpec.Name = expStatus.Name
+		experiments = append(experiments, expSpec)
+	}
+	return experiments
+}
+
+// legacyPreSpecAnnotation is the spec before the last change, written by the older operator
+const legacyPreSpecAnnotation = "preSpec"
+
+// migrateAppliedSpecs snapshots the applied specs of the chaosblades written by the older operator on startup
+func (r *ReconcileChaosBlade) migrateAppliedSpecs(ctx context.Context) {
+	blades := &v1alpha1.ChaosBladeList{}
+	if err := r.client.List(ctx, blades); err != nil {
+		logrus.WithError(err).Errorln("list the chaosblades to migrate the applied specs failed")
+		return
+	}
+	for idx := range blades.Items {
+		if !snapshotAppliedSpecs(blades.Items[idx].DeepCopy()) {
+			continue
+		}
+		logFields := logrus.WithField("blade", blades.Items[idx].Name)
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			cb := &v1alpha1.ChaosBlade{}
+			if err := r.client.Get(ctx, types.NamespacedName{Name: blades.Items[idx].Name}, cb); err != nil {
+				return err
+			}
+			if !snapshotAppliedSpecs(cb) {
+				return nil
+			}
+			return r.client.Status().Update(ctx, cb)
+		})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			logFields.WithError(err).Errorln("migrate the applied specs failed, the experiments are applied again")
+			continue
+		}
+		logFields.Infoln("migrate the applied specs of the chaosblade written by the older operator")
+	}
+}
+
+// snapshotAppliedSpecs sets the applied specs of the statuses without the snapshot, and returns true if any is
+// set. The spec in the preSpec annotation is applied if it matches the statuses, since the older operator writes
+// it when the spec is changed. Otherwise the current spec is the best known.
+func snapshotAppliedSpecs(cb *v1alpha1.ChaosBlade) bool {
+	experiments := cb.Spec.DeepCopy().Experiments
+	if value := cb.GetAnnotations()[legacyPreSpecAnnotation]; value != "" {
+		preSpec := v1alpha1.ChaosBladeSpec{}
+		if err := json.Unmarshal([]byte(value), &preSpec); err != nil {
+			logrus.WithField("blade", cb.Name).WithError(err).Warnln("unmarshal the preSpec annotation failed")
+		} else if matchesStatuses(preSpec.Experiments, cb.Status.ExpStatuses) {
+			experiments = preSpec.Experiments
+		}
+	}
+	// the legacy statuses have no names, they are named like the spec
+	setExperimentNames(&v1alpha1.ChaosBladeSpec{Experiments: experiments})
+	migrated := false
+	for idx := range cb.Status.ExpStatuses {
+		expStatus := &cb.Status.ExpStatuses[idx]
+		if expStatus.AppliedSpec != nil || idx >= len(experiments) ||
+			expStatus.Scope != experiments[idx].Scope || expStatus.Target != experiments[idx].Target ||
+			expStatus.Action != experiments[idx].Action {
+			continue
+		}
+		if expStatus.Name == "" {
+			expStatus.Name = experiments[idx].Name
+		}
+		expStatus.AppliedSpec = experiments[idx].DeepCopy()
+		expStatus.AppliedSpec.Name = expStatus.Name
+		migrated = true
+	}
+	return migrated
+}
+
+// matchesStatuses returns true if the experiments are the ones of the statuses in order
+func matchesStatuses(experiments []v1alpha1.ExperimentSpec, expStatuses []v1alpha1.ExperimentStatus) bool {
+	if len(experiments) != len(expStatuses) {
+		return false
+	}
+	for idx, expStatus := range expStatuses {
+		if expStatus.Scope != experiments[idx].Scope || expStatus.Target != experiments[idx].Target ||
+			expStatus.Action != experiments[idx].Action {
+			return false
+		}
+	}
+	return true
+}
+
+// isSpecApplied returns true if the experiments in the spec are the same as the applied ones
+func isSpecApplied(cb *v1alpha1.ChaosBlade) bool {
+	applied := appliedExperiments(cb)
+	if len(applied) != len(cb.Spec.Experiments) {
+		return false
+	}
+	for idx, expSpec := range cb.Spec.Experiments {
+		if applied[idx].Name != expSpec.Name || !isSameAction(applied[idx], expSpec) ||
+			len(model.ChangedFlags(applied[idx], expSpec)) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// updateExperiments applies the new spec by the diff of the experiments and returns the statuses of the new
+// experiments, the old statuses are in the order of the old experiments. The untouched experiments are left alone, the changed ones are updated in place if the action
+// supports it, otherwise destroyed and created again. The statuses of the removed experiments failed to destroy
+// are kept after the new ones, so the spec is not applied and the destroy is retried by the next reconcile.
+func (r *ReconcileChaosBlade) updateExperiments(reqLogger *logrus.Entry, cb *v1alpha1.ChaosBlade,
+	oldSpec, newSpec v1alpha1.ChaosBladeSpec, oldStatuses []v1alpha1.ExperimentStatus, parallelism int,
+) []v1alpha1.ExperimentStatus {
+	diff := diffExperiments(oldSpec.Experiments, newSpec.Experiments)
+	removedStatuses := make([]v1alpha1.ExperimentStatus, 0)
+	for _, i := range diff.removed {
+		destroyed := r.Executor.Destroy(cb.Name, oldSpec.Experiments[i], oldStatuses[i])
+		if !destroyed.Success {
+			reqLogger.WithField("experiment", i).Errorf("destroy the removed experiment failed, %s", destroyed.Error)
+			removedStatuses = append(removedStatuses, destroyFailedStatus("destroy the removed experiment failed",
+				oldSpec.Experiments[i], oldSpec.Experiments[i], oldStatuses[i], destroyed))
+		}
+	}
+	// the changed and the added experiments are applied concurrently like creating
+	expStatuses := make([]v1alpha1.ExperimentStatus, len(newSpec.Experiments))
+	model.ParallelizeExecWithWorkers(parallelism, len(newSpec.Experiments), func(j int) {
+		expSpec := newSpec.Experiments[j]
+		logFields := reqLogger.WithField("experiment", j)
+		i := diff.oldIndexes[j]
+		if i >= 0 {
+			oldStatus := oldStatuses[i]
+			if !diff.changed[j] {
+				oldStatus.Name = expSpec.Name
+				oldStatus.AppliedSpec = expSpec.DeepCopy()
+				expStatuses[j] = oldStatus
+				return
+			}
+			if expStatus, ok := r.Executor.Update(cb.Name, oldSpec.Experiments[i], expSpec, oldStatus); ok {
+				logFields.Infoln("the experiment is updated in place")
+				// the targets are not changed, so the ramp goes on
+				expStatus.Ramp = oldStatus.Ramp
+				expStatuses[j] = scheduleRetry(expSpec, expStatus, time.Now())
+				return
+			}
+			destroyed := r.Executor.Destroy(cb.Name, oldSpec.Experiments[i], oldStatus)
+			if !destroyed.Success {
+				logFields.Errorf("destroy the changed experiment failed, %s", destroyed.Error)
+				expStatuses[j] = destroyFailedStatus("destroy the old experiment failed, the new experiment is not created",
+					expSpec, oldSpec.Experiments[i], oldStatus, destroyed)
+				return
+			}
+		}
+		created := r.createExperiments(reqLogger, cb, []v1alpha1.ExperimentSpec{expSpec}, 1, false)[0]
+		expStatuses[j] = scheduleRetry(expSpec, created, time.Now())
+	})
+	return append(expStatuses, removedStatuses...)
+}
+
+// destroyFailedStatus returns the status of the old experiment failed to destroy, which keeps the targets not
+// destroyed and the applied spec of the old experiment, so the spec is applied again by the next reconcile
+func destroyFailedStatus(message string, expSpec, oldExpSpec v1alpha1.ExperimentSpec,
+	oldExpStatus, destroyed v1alpha1.ExperimentStatus,
+) v1alpha1.ExperimentStatus {
+	expStatus := v1alpha1.CreateFailExperimentStatus(message, remainingTargets(oldExpStatus, destroyed))
+	expStatus.Scope = expSpec.Scope
+	expStatus.Target = expSpec.Target
+	expStatus.Action = expSpec.Action
+	expStatus.Name = expSpec.Name
+	expStatus.AppliedSpec = oldExpSpec.DeepCopy()
+	expStatus.AppliedSpec.Name = expSpec.Name
+	return expStatus
+}
+
+// remainingTargets returns the targets of the old experiment which are not destroyed
+func remainingTargets(oldExpStatus, destroyed v1alpha1.ExperimentStatus) []v1alpha1.ResourceStatus {
+	destroyedTargets := make(map[string]bool, len(destroyed.ResStatuses))
+	for _, rsStatus := range destroyed.ResStatuses {
+		if rsStatus.Success {
+			destroyedTargets[rsStatus.Identifier+"/"+rsStatus.Id] = true
+		}
+	}
+	remaining := make([]v1alpha1.ResourceStatus, 0)
+	for _, rsStatus := range oldExpStatus.ResStatuses {
+		if rsStatus.Success && !destroyedTargets[rsStatus.Identifier+"/"+rsStatus.Id] {
+			remaining = append(remaining, rsStatus)
+		}
+	}
+	return remaining
+}
+
+// experimentParallelism returns the max number of the experiments of the chaosblade applied concurrently
+func experimentParallelism(cb *v1alpha1.ChaosBlade) int {
+	parallelism := int(cb.Spec.Parallelism)
+	if parallelism <= 0 {
+		parallelism = chaosblade.ExperimentParallelism
+	}
+	if parallelism <= 0 {
+		return 1
+	}
+	return parallelism
+}
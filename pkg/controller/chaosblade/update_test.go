@@ -0,0 +1,251 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chaosblade
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/chaosblade-io/chaosblade-operator/exec/model"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/apis/chaosblade/v1alpha1"
+)
+
+func newExperimentSpec(target, action string, matchers ...v1alpha1.FlagSpec) v1alpha1.ExperimentSpec {
+	return v1alpha1.ExperimentSpec{Scope: "pod", Target: target, Action: action, Matchers: matchers}
+}
+
+func Test_diffExperiments(t *testing.T) {
+	names := v1alpha1.FlagSpec{Name: "names", Value: []string{"web-0"}}
+	oldExps := []v1alpha1.ExperimentSpec{
+		newExperimentSpec("network", "delay", names, v1alpha1.FlagSpec{Name: "time", Value: []string{"100"}}),
+		newExperimentSpec("cpu", "fullload", names),
+		newExperimentSpec("pod", "IO", names, v1alpha1.FlagSpec{Name: "delay", Value: []string{"100"}}),
+	}
+	newExps := []v1alpha1.ExperimentSpec{
+		// reordered matchers
+		newExperimentSpec("pod", "IO", v1alpha1.FlagSpec{Name: "delay", Value: []string{"100"}}, names),
+		newExperimentSpec("network", "delay", names, v1alpha1.FlagSpec{Name: "time", Value: []string{"200"}}),
+		newExperimentSpec("mem", "load", names),
+	}
+	diff := diffExperiments(oldExps, newExps)
+	if !reflect.DeepEqual(diff.oldIndexes, []int{2, 0, -1}) {
+		t.Errorf("unexpected paired old experiments %v", diff.oldIndexes)
+	}
+	if !reflect.DeepEqual(diff.changed, []bool{false, true, false}) {
+		t.Errorf("unexpected changed experiments %v", diff.changed)
+	}
+	if !reflect.DeepEqual(diff.removed, []int{1}) {
+		t.Errorf("unexpected removed experiments %v", diff.removed)
+	}
+}
+
+func Test_remainingTargets(t *testing.T) {
+	oldExpStatus := v1alpha1.ExperimentStatus{ResStatuses: []v1alpha1.ResourceStatus{
+		{Id: "a", Identifier: "default/node1/web-0", Success: true},
+		{Id: "b", Identifier: "default/node1/web-1", Success: true},
+		{Identifier: "default/node1/web-2", Success: false},
+	}}
+	destroyed := v1alpha1.ExperimentStatus{ResStatuses: []v1alpha1.ResourceStatus{
+		{Id: "a", Identifier: "default/node1/web-0", Success: true},
+		{Id: "b", Identifier: "default/node1/web-1", Success: false},
+	}}
+	remaining := remainingTargets(oldExpStatus, destroyed)
+	if len(remaining) != 1 || remaining[0].Id != "b" {
+		t.Errorf("unexpected remaining targets %+v", remaining)
+	}
+}
+
+func Test_isSpecApplied(t *testing.T) {
+	names := v1alpha1.FlagSpec{Name: "names", Value: []string{"web-0"}}
+	delay := newExperimentSpec("network", "delay", names, v1alpha1.FlagSpec{Name: "time", Value: []string{"100"}})
+	cb := &v1alpha1.ChaosBlade{
+		Spec: v1alpha1.ChaosBladeSpec{Experiments: []v1alpha1.ExperimentSpec{delay}},
+		// the status without the snapshot is written by the older operator
+		Status: v1alpha1.ChaosBladeStatus{ExpStatuses: []v1alpha1.ExperimentStatus{
+			{Scope: "pod", Target: "network", Action: "delay"},
+		}},
+	}
+	if isSpecApplied(cb) {
+		t.Errorf("the live spec must not be applied if the status has no snapshot")
+	}
+	cb.Status.ExpStatuses[0].AppliedSpec = delay.DeepCopy()
+	cb.Spec.Experiments[0].Matchers = append([]v1alpha1.FlagSpec{}, names, v1alpha1.FlagSpec{Name: "time", Value: []string{"200"}})
+	if isSpecApplied(cb) {
+		t.Errorf("the changed spec must not be applied")
+	}
+	if applied := appliedExperiments(cb); !reflect.DeepEqual(applied[0], delay) {
+		t.Errorf("unexpected applied experiment %+v", applied[0])
+	}
+}
+
+func Test_rampOnlyEdit(t *testing.T) {
+	names := v1alpha1.FlagSpec{Name: "names", Value: []string{"web-0", "web-1"}}
+	delay := newExperimentSpec("network", "delay", names, v1alpha1.FlagSpec{Name: "time", Value: []string{"100"}})
+	delay.Name = "delay"
+	delay.Ramp = []v1alpha1.RampStep{{Count: 1, Hold: metav1.Duration{Duration: time.Minute}}, {Percent: 100}}
+	ramped := *delay.DeepCopy()
+	ramped.Ramp[0].Hold = metav1.Duration{Duration: 5 * time.Minute}
+
+	cb := &v1alpha1.ChaosBlade{
+		Spec: v1alpha1.ChaosBladeSpec{Experiments: []v1alpha1.ExperimentSpec{ramped}},
+		Status: v1alpha1.ChaosBladeStatus{ExpStatuses: []v1alpha1.ExperimentStatus{
+			{Name: "delay", Scope: "pod", Target: "network", Action: "delay", AppliedSpec: delay.DeepCopy()},
+		}},
+	}
+	if isSpecApplied(cb) {
+		t.Errorf("the spec with the changed ramp must not be applied")
+	}
+	diff := diffExperiments([]v1alpha1.ExperimentSpec{delay}, []v1alpha1.ExperimentSpec{ramped})
+	if !reflect.DeepEqual(diff.oldIndexes, []int{0}) || !reflect.DeepEqual(diff.changed, []bool{true}) {
+		t.Errorf("expected the experiment with the changed ramp paired and changed, got %+v", diff)
+	}
+	// the unnamed experiment with the changed ramp is paired as a changed one
+	delay.Name, ramped.Name = "", ""
+	diff = diffExperiments([]v1alpha1.ExperimentSpec{delay}, []v1alpha1.ExperimentSpec{ramped})
+	if !reflect.DeepEqual(diff.oldIndexes, []int{0}) || !reflect.DeepEqual(diff.changed, []bool{true}) {
+		t.Errorf("expected the unnamed experiment with the changed ramp paired and changed, got %+v", diff)
+	}
+	if changed := model.ChangedFlags(delay, ramped); !reflect.DeepEqual(changed, []string{model.RampChangedFlag}) {
+		t.Errorf("unexpected changed flags %v", changed)
+	}
+}
+
+func Test_updateExperimentsWithRemovedDestroyFailed(t *testing.T) {
+	names := v1alpha1.FlagSpec{Name: "names", Value: []string{"web-0"}}
+	delay := newExperimentSpec("network", "delay", names)
+	delay.Name = "delay"
+	loss := newExperimentSpec("network", "loss", names)
+	loss.Name = "loss"
+	oldStatuses := []v1alpha1.ExperimentStatus{
+		setStatusSpec(v1alpha1.CreateSuccessExperimentStatus([]v1alpha1.ResourceStatus{
+			{Id: "a", Identifier: "default/node1/web-0", Success: true},
+		}), delay),
+		setStatusSpec(v1alpha1.CreateSuccessExperimentStatus([]v1alpha1.ResourceStatus{
+			{Id: "b", Identifier: "default/node1/web-0", Success: true},
+		}), loss),
+	}
+	cb := &v1alpha1.ChaosBlade{
+		ObjectMeta: metav1.ObjectMeta{Name: "blade"},
+		Spec:       v1alpha1.ChaosBladeSpec{Experiments: []v1alpha1.ExperimentSpec{delay}},
+	}
+	r := &ReconcileChaosBlade{Executor: &fakeExecutor{destroyFailed: true}}
+	oldSpec := v1alpha1.ChaosBladeSpec{Experiments: []v1alpha1.ExperimentSpec{delay, loss}}
+	cb.Status.ExpStatuses = r.updateExperiments(logrus.WithField("test", t.Name()), cb, oldSpec, cb.Spec, oldStatuses, 1)
+	if len(cb.Status.ExpStatuses) != 2 {
+		t.Fatalf("expected the status of the removed experiment kept, got %+v", cb.Status.ExpStatuses)
+	}
+	removed := cb.Status.ExpStatuses[1]
+	if removed.Success || removed.Name != "loss" || removed.AppliedSpec == nil || removed.AppliedSpec.Action != "loss" ||
+		len(removed.ResStatuses) != 1 || removed.ResStatuses[0].Id != "b" {
+		t.Errorf("unexpected status of the removed experiment %+v", removed)
+	}
+	if isSpecApplied(cb) {
+		t.Errorf("the spec must not be applied until the removed experiment is destroyed")
+	}
+	// the next reconcile destroys the removed experiment again
+	r.Executor = &fakeExecutor{}
+	cb.Status.ExpStatuses = r.updateExperiments(logrus.WithField("test", t.Name()), cb,
+		v1alpha1.ChaosBladeSpec{Experiments: appliedExperiments(cb)}, cb.Spec, cb.Status.ExpStatuses, 1)
+	if len(cb.Status.ExpStatuses) != 1 || !isSpecApplied(cb) {
+		t.Errorf("expected the removed experiment destroyed, got %+v", cb.Status.ExpStatuses)
+	}
+}
+
+func Test_snapshotAppliedSpecs(t *testing.T) {
+	names := v1alpha1.FlagSpec{Name: "names", Value: []string{"web-0"}}
+	delay100 := newExperimentSpec("network", "delay", names, v1alpha1.FlagSpec{Name: "time", Value: []string{"100"}})
+	delay200 := newExperimentSpec("network", "delay", names, v1alpha1.FlagSpec{Name: "time", Value: []string{"200"}})
+	cpu := newExperimentSpec("cpu", "fullload", names)
+	legacyStatus := v1alpha1.ExperimentStatus{Scope: "pod", Target: "network", Action: "delay"}
+	// the legacy status is named like the spec
+	named := func(expSpec v1alpha1.ExperimentSpec) v1alpha1.ExperimentSpec {
+		expSpec.Name = "network-delay"
+		return expSpec
+	}
+	tests := []struct {
+		name         string
+		preSpec      string
+		experiments  []v1alpha1.ExperimentSpec
+		expStatus    v1alpha1.ExperimentStatus
+		wantMigrated bool
+		wantApplied  v1alpha1.ExperimentSpec
+	}{
+		{
+			name:         "migrate from the preSpec annotation",
+			preSpec:      `{"experiments":[{"scope":"pod","target":"network","action":"delay","matchers":[{"name":"names","value":["web-0"]},{"name":"time","value":["100"]}]}]}`,
+			experiments:  []v1alpha1.ExperimentSpec{delay200},
+			expStatus:    legacyStatus,
+			wantMigrated: true,
+			wantApplied:  named(delay100),
+		},
+		{
+			name:         "snapshot the current spec without the annotation",
+			experiments:  []v1alpha1.ExperimentSpec{delay200},
+			expStatus:    legacyStatus,
+			wantMigrated: true,
+			wantApplied:  named(delay200),
+		},
+		{
+			name:         "snapshot the current spec if the preSpec does not match",
+			preSpec:      `{"experiments":[{"scope":"pod","target":"cpu","action":"fullload"}]}`,
+			experiments:  []v1alpha1.ExperimentSpec{delay200},
+			expStatus:    legacyStatus,
+			wantMigrated: true,
+			wantApplied:  named(delay200),
+		},
+		{
+			name:        "skip the different action",
+			experiments: []v1alpha1.ExperimentSpec{cpu},
+			expStatus:   legacyStatus,
+		},
+		{
+			name:        "skip the status with the snapshot",
+			experiments: []v1alpha1.ExperimentSpec{delay200},
+			expStatus: v1alpha1.ExperimentStatus{Scope: "pod", Target: "network", Action: "delay",
+				AppliedSpec: delay100.DeepCopy()},
+			wantApplied: delay100,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb := &v1alpha1.ChaosBlade{
+				Spec:   v1alpha1.ChaosBladeSpec{Experiments: tt.experiments},
+				Status: v1alpha1.ChaosBladeStatus{ExpStatuses: []v1alpha1.ExperimentStatus{tt.expStatus}},
+			}
+			if tt.preSpec != "" {
+				cb.SetAnnotations(map[string]string{legacyPreSpecAnnotation: tt.preSpec})
+			}
+			if migrated := snapshotAppliedSpecs(cb); migrated != tt.wantMigrated {
+				t.Errorf("snapshotAppliedSpecs() = %t, want %t", migrated, tt.wantMigrated)
+			}
+			applied := cb.Status.ExpStatuses[0].AppliedSpec
+			if tt.wantApplied.Action == "" {
+				if applied != nil {
+					t.Errorf("unexpected applied spec %+v", applied)
+				}
+				return
+			}
+			if applied == nil || !reflect.DeepEqual(*applied, tt.wantApplied) {
+				t.Errorf("unexpected applied spec %+v, want %+v", applied, tt.wantApplied)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hookfs
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade-operator/pkg/auth"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/certs"
+)
+
+func TestChaosBladeHookClient_secure(t *testing.T) {
+	ca, err := certs.GenerateCA("test-ca", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serving, err := certs.GenerateServingCert(ca, AuthServerName, []string{AuthServerName}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPair, err := tls.X509KeyPair(serving.Cert, serving.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewUnstartedServer(auth.RequireToken("secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	})))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{keyPair}}
+	server.StartTLS()
+	defer server.Close()
+	addr := strings.TrimPrefix(server.URL, "https://")
+
+	client, err := NewSecureChaosbladeHookClient(addr, "secret", ca.Cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Status(context.Background()); err != nil {
+		t.Errorf("Status() with the valid token error = %v", err)
+	}
+
+	client, err = NewSecureChaosbladeHookClient(addr, "other", ca.Cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Status(context.Background()); err == nil {
+		t.Errorf("Status() with the wrong token succeeded")
+	}
+
+	other, err := certs.GenerateCA("other-ca", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err = NewSecureChaosbladeHookClient(addr, "secret", other.Cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Status(context.Background()); err == nil {
+		t.Errorf("Status() trusting another ca succeeded")
+	}
+
+	if _, err := NewSecureChaosbladeHookClient(addr, "secret", []byte("invalid")); err == nil {
+		t.Errorf("NewSecureChaosbladeHookClient() with the invalid ca succeeded")
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hookfs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/hanwen/go-fuse/fuse"
+)
+
+var procPath = "/proc"
+
+// matchCaller returns true if the process issuing the file system call is selected by the rule.
+// The uid filter must be satisfied, and then either the pid or the process name filter.
+// The caller pid is only visible when the sidecar shares the process namespace with the pod,
+// otherwise the kernel reports 0 and the pid and process filters never match.
+func (m *InjectMessage) matchCaller(caller *fuse.Context) bool {
+	if len(m.Pids) == 0 && len(m.ProcessNames) == 0 && len(m.Uids) == 0 {
+		return true
+	}
+	if caller == nil {
+		return false
+	}
+	if len(m.Uids) > 0 && !containsUint32(m.Uids, caller.Uid) {
+		return false
+	}
+	if len(m.Pids) == 0 && len(m.ProcessNames) == 0 {
+		return true
+	}
+	if caller.Pid == 0 {
+		return false
+	}
+	// the caller pid may be a thread id, so match the thread group as well
+	tgid := getTgid(caller.Pid)
+	if containsUint32(m.Pids, caller.Pid) || containsUint32(m.Pids, tgid) {
+		return true
+	}
+	if len(m.ProcessNames) > 0 {
+		for _, name := range getProcessNames(tgid) {
+			for _, processName := range m.ProcessNames {
+				if name == processName {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// getTgid returns the thread group id of the pid, or the pid itself if it cannot be resolved
+func getTgid(pid uint32) uint32 {
+	file, err := os.Open(path.Join(procPath, strconv.FormatUint(uint64(pid), 10), "status"))
+	if err != nil {
+		return pid
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "Tgid:") {
+			continue
+		}
+		tgid, err := strconv.ParseUint(strings.TrimSpace(strings.TrimPrefix(line, "Tgid:")), 10, 32)
+		if err != nil {
+			return pid
+		}
+		return uint32(tgid)
+	}
+	return pid
+}
+
+// getProcessNames returns the comm and the executable base name of the pid. The comm
+// is truncated to 15 characters by the kernel, so the executable name is used for long names.
+func getProcessNames(pid uint32) []string {
+	names := make([]string, 0, 2)
+	procDir := path.Join(procPath, fmt.Sprint(pid))
+	if comm, err := ioutil.ReadFile(path.Join(procDir, "comm")); err == nil {
+		names = append(names, strings.TrimSpace(string(comm)))
+	}
+	if cmdline, err := ioutil.ReadFile(path.Join(procDir, "cmdline")); err == nil && len(cmdline) > 0 {
+		executable := string(bytes.SplitN(cmdline, []byte{0}, 2)[0])
+		if executable != "" {
+			names = append(names, path.Base(executable))
+		}
+	}
+	return names
+}
+
+func containsUint32(values []uint32, value uint32) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hookfs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hanwen/go-fuse/fuse"
+)
+
+func TestInjectMessage_matchCaller(t *testing.T) {
+	pid := uint32(os.Getpid())
+	uid := uint32(os.Getuid())
+	self := &fuse.Context{Owner: fuse.Owner{Uid: uid}, Pid: pid}
+	names := getProcessNames(pid)
+	if len(names) == 0 {
+		t.Skip("Skipping: cannot read the process name from /proc")
+	}
+	tests := []struct {
+		name   string
+		msg    InjectMessage
+		caller *fuse.Context
+		want   bool
+	}{
+		{name: "no filter", msg: InjectMessage{}, caller: nil, want: true},
+		{name: "unknown caller", msg: InjectMessage{Uids: []uint32{uid}}, caller: nil, want: false},
+		{name: "uid matched", msg: InjectMessage{Uids: []uint32{uid}}, caller: self, want: true},
+		{name: "uid not matched", msg: InjectMessage{Uids: []uint32{uid + 1}}, caller: self, want: false},
+		{name: "pid matched", msg: InjectMessage{Pids: []uint32{pid}}, caller: self, want: true},
+		{name: "pid not matched", msg: InjectMessage{Pids: []uint32{pid + 1}}, caller: self, want: false},
+		{name: "pid invisible", msg: InjectMessage{Pids: []uint32{pid}}, caller: &fuse.Context{}, want: false},
+		{name: "process matched", msg: InjectMessage{ProcessNames: []string{names[0]}}, caller: self, want: true},
+		{name: "process not matched", msg: InjectMessage{ProcessNames: []string{"not-exists"}}, caller: self, want: false},
+		{name: "uid and process", msg: InjectMessage{Uids: []uint32{uid + 1}, ProcessNames: []string{names[0]}}, caller: self, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.msg.matchCaller(tt.caller); got != tt.want {
+				t.Errorf("matchCaller() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
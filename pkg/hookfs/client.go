@@ -17,7 +17,10 @@
 package hookfs
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
@@ -25,68 +28,128 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/chaosblade-io/chaosblade-spec-go/util"
 	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaosblade-operator/pkg/auth"
 )
 
 type ChaosBladeHookClient struct {
 	client *http.Client
 	addr   string
+	scheme string
+	token  string
 }
 
 func NewChabladeHookClient(addr string) *ChaosBladeHookClient {
 	return &ChaosBladeHookClient{
-		addr: addr,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-			Transport: &http.Transport{
-				DialContext: (&net.Dialer{
-					Timeout: 5 * time.Second,
-				}).DialContext,
-				DisableKeepAlives: true,
-			},
+		addr:   addr,
+		scheme: "http",
+		client: newHttpClient(nil),
+	}
+}
+
+// NewSecureChaosbladeHookClient returns the client which verifies the hook server by the caPEM
+// and authenticates itself by the bearer token
+func NewSecureChaosbladeHookClient(addr, token string, caPEM []byte) (*ChaosBladeHookClient, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, errors.New("invalid ca certificate of the hook server")
+	}
+	return &ChaosBladeHookClient{
+		addr:   addr,
+		scheme: "https",
+		token:  token,
+		client: newHttpClient(&tls.Config{
+			RootCAs:    pool,
+			ServerName: AuthServerName,
+			MinVersion: tls.VersionTLS12,
+		}),
+	}, nil
+}
+
+func newHttpClient(tlsConfig *tls.Config) *http.Client {
+	return &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout: 5 * time.Second,
+			}).DialContext,
+			TLSClientConfig:   tlsConfig,
+			DisableKeepAlives: true,
 		},
 	}
 }
 
 func (c *ChaosBladeHookClient) InjectFault(ctx context.Context, injectMsg *InjectMessage) error {
-	url := "http://" + c.addr + InjectPath
 	body, err := json.Marshal(injectMsg)
 	if err != nil {
 		return err
 	}
 	logrus.WithField("injectMsg", injectMsg).Infoln("Inject fault")
-	result, err, code := util.PostCurl(url, body, "application/json")
+	result, err := c.do(ctx, http.MethodPost, InjectPath, body)
 	if err != nil {
 		return err
 	}
 	logrus.WithField("injectMsg", injectMsg).Infof("Response is %s", result)
-	if code != http.StatusOK {
-		return errors.New(result)
-	}
 	return nil
 }
 
 func (c *ChaosBladeHookClient) Revoke(ctx context.Context) error {
-	url := "http://" + c.addr + RecoverPath
-	req, err := http.NewRequest("GET", url, nil)
+	result, err := c.do(ctx, http.MethodGet, RecoverPath, nil)
 	if err != nil {
 		return err
 	}
+	logrus.Infof("Revoke fault, response is %s", result)
+	return nil
+}
+
+// Lease extends the expiration of the active rules
+func (c *ChaosBladeHookClient) Lease(ctx context.Context) error {
+	_, err := c.do(ctx, http.MethodPost, LeasePath, nil)
+	return err
+}
+
+// Shutdown stops the fuse server attached by the ephemeral container
+func (c *ChaosBladeHookClient) Shutdown(ctx context.Context) error {
+	_, err := c.do(ctx, http.MethodPost, ShutdownPath, nil)
+	return err
+}
+
+// Status returns the active rules with their hit counters
+func (c *ChaosBladeHookClient) Status(ctx context.Context) ([]RuleStatus, error) {
+	result, err := c.do(ctx, http.MethodGet, StatusPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	var statuses []RuleStatus
+	if err := json.Unmarshal(result, &statuses); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+// do sends the request to the hook server and returns the response body if the status code is ok
+func (c *ChaosBladeHookClient) do(ctx context.Context, method, path string, body []byte) ([]byte, error) {
+	url := c.scheme + "://" + c.addr + path
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
 	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", auth.BearerPrefix+c.token)
+	}
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
-	bytes, err := ioutil.ReadAll(resp.Body)
+	result, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	result := string(bytes)
-	logrus.Infof("Revoke fault, response is %s", result)
 	if resp.StatusCode != http.StatusOK {
-		return errors.New(result)
+		return nil, errors.New(string(result))
 	}
-	return nil
+	return result, nil
 }
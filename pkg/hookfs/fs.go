@@ -0,0 +1,410 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hookfs
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hanwen/go-fuse/fuse"
+	"github.com/hanwen/go-fuse/fuse/nodefs"
+	"github.com/hanwen/go-fuse/fuse/pathfs"
+)
+
+const fsName = "chaosbladefs"
+
+// ChaosbladeFs is a loopback file system which consults the ChaosbladeHook before
+// every call, passing the caller of the request so that rules can be scoped to
+// processes or users.
+type ChaosbladeFs struct {
+	pathfs.FileSystem
+	Original   string
+	MountPoint string
+	hook       *ChaosbladeHook
+	// opening holds the name of the file being opened by the request context,
+	// handed over to the raw file system which assigns the file handle
+	opening sync.Map
+	// handles maps the file handles to the names of the opened files
+	handles sync.Map
+	// statFsLock serializes the statfs requests, statFsName is the name of the last one
+	statFsLock sync.Mutex
+	statFsName string
+}
+
+func NewChaosbladeFs(original, mountpoint string) *ChaosbladeFs {
+	return &ChaosbladeFs{
+		FileSystem: pathfs.NewLoopbackFileSystem(original),
+		Original:   original,
+		MountPoint: mountpoint,
+		hook:       &ChaosbladeHook{MountPoint: mountpoint},
+	}
+}
+
+func (fs *ChaosbladeFs) String() string {
+	return fmt.Sprintf("ChaosbladeFs{Original=%s, MountPoint=%s}", fs.Original, fs.MountPoint)
+}
+
+// Serve mounts the file system and serves requests until it is unmounted
+func (fs *ChaosbladeFs) Serve() error {
+	opts := &nodefs.Options{
+		NegativeTimeout: time.Second,
+		AttrTimeout:     time.Second,
+		EntryTimeout:    time.Second,
+	}
+	pathFs := pathfs.NewPathNodeFs(fs, &pathfs.PathNodeFsOptions{ClientInodes: true})
+	conn := nodefs.NewFileSystemConnector(pathFs.Root(), opts)
+	originalAbs, _ := filepath.Abs(fs.Original)
+	server, err := fuse.NewServer(&chaosbladeRawFs{RawFileSystem: conn.RawFS(), fs: fs}, fs.MountPoint, &fuse.MountOptions{
+		AllowOther: true,
+		Name:       fsName,
+		FsName:     originalAbs,
+	})
+	if err != nil {
+		return err
+	}
+	server.Serve()
+	return nil
+}
+
+func (fs *ChaosbladeFs) GetAttr(name string, context *fuse.Context) (*fuse.Attr, fuse.Status) {
+	if err := fs.hook.doInjectFault(name, "getattr", context); err != nil {
+		return nil, fuse.ToStatus(err)
+	}
+	return fs.FileSystem.GetAttr(name, context)
+}
+
+func (fs *ChaosbladeFs) Chmod(name string, mode uint32, context *fuse.Context) fuse.Status {
+	if err := fs.hook.doInjectFault(name, "chmod", context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	return fs.FileSystem.Chmod(name, mode, context)
+}
+
+func (fs *ChaosbladeFs) Chown(name string, uid uint32, gid uint32, context *fuse.Context) fuse.Status {
+	if err := fs.hook.doInjectFault(name, "chown", context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	return fs.FileSystem.Chown(name, uid, gid, context)
+}
+
+func (fs *ChaosbladeFs) Utimens(name string, atime *time.Time, mtime *time.Time, context *fuse.Context) fuse.Status {
+	if err := fs.hook.doInjectFault(name, "utimens", context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	return fs.FileSystem.Utimens(name, atime, mtime, context)
+}
+
+func (fs *ChaosbladeFs) Truncate(name string, size uint64, context *fuse.Context) fuse.Status {
+	if err := fs.hook.doInjectFault(name, "truncate", context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	return fs.FileSystem.Truncate(name, size, context)
+}
+
+func (fs *ChaosbladeFs) Access(name string, mode uint32, context *fuse.Context) fuse.Status {
+	if err := fs.hook.doInjectFault(name, "access", context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	return fs.FileSystem.Access(name, mode, context)
+}
+
+func (fs *ChaosbladeFs) Link(oldName string, newName string, context *fuse.Context) fuse.Status {
+	if err := fs.hook.doInjectFault(oldName, "link", context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	if err := fs.hook.doInjectFault(newName, "link", context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	return fs.FileSystem.Link(oldName, newName, context)
+}
+
+func (fs *ChaosbladeFs) Mkdir(name string, mode uint32, context *fuse.Context) fuse.Status {
+	if err := fs.hook.doInjectFault(name, "mkdir", context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	return fs.FileSystem.Mkdir(name, mode, context)
+}
+
+func (fs *ChaosbladeFs) Mknod(name string, mode uint32, dev uint32, context *fuse.Context) fuse.Status {
+	if err := fs.hook.doInjectFault(name, "mknod", context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	return fs.FileSystem.Mknod(name, mode, dev, context)
+}
+
+func (fs *ChaosbladeFs) Rename(oldName string, newName string, context *fuse.Context) fuse.Status {
+	if err := fs.hook.doInjectFault(oldName, "rename", context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	if err := fs.hook.doInjectFault(newName, "rename", context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	return fs.FileSystem.Rename(oldName, newName, context)
+}
+
+func (fs *ChaosbladeFs) Rmdir(name string, context *fuse.Context) fuse.Status {
+	if err := fs.hook.doInjectFault(name, "rmdir", context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	return fs.FileSystem.Rmdir(name, context)
+}
+
+func (fs *ChaosbladeFs) Unlink(name string, context *fuse.Context) fuse.Status {
+	if err := fs.hook.doInjectFault(name, "unlink", context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	return fs.FileSystem.Unlink(name, context)
+}
+
+func (fs *ChaosbladeFs) GetXAttr(name string, attribute string, context *fuse.Context) ([]byte, fuse.Status) {
+	if err := fs.hook.doInjectFault(name, "getxattr", context); err != nil {
+		return nil, fuse.ToStatus(err)
+	}
+	return fs.FileSystem.GetXAttr(name, attribute, context)
+}
+
+func (fs *ChaosbladeFs) ListXAttr(name string, context *fuse.Context) ([]string, fuse.Status) {
+	if err := fs.hook.doInjectFault(name, "listxattr", context); err != nil {
+		return nil, fuse.ToStatus(err)
+	}
+	return fs.FileSystem.ListXAttr(name, context)
+}
+
+func (fs *ChaosbladeFs) RemoveXAttr(name string, attr string, context *fuse.Context) fuse.Status {
+	if err := fs.hook.doInjectFault(name, "removexattr", context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	return fs.FileSystem.RemoveXAttr(name, attr, context)
+}
+
+func (fs *ChaosbladeFs) SetXAttr(name string, attr string, data []byte, flags int, context *fuse.Context) fuse.Status {
+	if err := fs.hook.doInjectFault(name, "setxattr", context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	return fs.FileSystem.SetXAttr(name, attr, data, flags, context)
+}
+
+func (fs *ChaosbladeFs) Open(name string, flags uint32, context *fuse.Context) (nodefs.File, fuse.Status) {
+	if err := fs.hook.doInjectFault(name, "open", context); err != nil {
+		return nil, fuse.ToStatus(err)
+	}
+	file, code := fs.FileSystem.Open(name, flags, context)
+	if !code.Ok() {
+		return file, code
+	}
+	fs.opening.Store(context, name)
+	return newChaosbladeFile(file, name), code
+}
+
+func (fs *ChaosbladeFs) Create(name string, flags uint32, mode uint32, context *fuse.Context) (nodefs.File, fuse.Status) {
+	if err := fs.hook.doInjectFault(name, "create", context); err != nil {
+		return nil, fuse.ToStatus(err)
+	}
+	file, code := fs.FileSystem.Create(name, flags, mode, context)
+	if !code.Ok() {
+		return file, code
+	}
+	fs.opening.Store(context, name)
+	return newChaosbladeFile(file, name), code
+}
+
+func (fs *ChaosbladeFs) OpenDir(name string, context *fuse.Context) ([]fuse.DirEntry, fuse.Status) {
+	if err := fs.hook.doInjectFault(name, "opendir", context); err != nil {
+		return nil, fuse.ToStatus(err)
+	}
+	return fs.FileSystem.OpenDir(name, context)
+}
+
+func (fs *ChaosbladeFs) Symlink(value string, linkName string, context *fuse.Context) fuse.Status {
+	if err := fs.hook.doInjectFault(value, "symlink", context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	if err := fs.hook.doInjectFault(linkName, "symlink", context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	return fs.FileSystem.Symlink(value, linkName, context)
+}
+
+func (fs *ChaosbladeFs) Readlink(name string, context *fuse.Context) (string, fuse.Status) {
+	if err := fs.hook.doInjectFault(name, "readlink", context); err != nil {
+		return "", fuse.ToStatus(err)
+	}
+	return fs.FileSystem.Readlink(name, context)
+}
+
+// StatFs records the name for the raw file system, which injects the fault with the caller
+func (fs *ChaosbladeFs) StatFs(name string) *fuse.StatfsOut {
+	fs.statFsName = name
+	return fs.FileSystem.StatFs(name)
+}
+
+// chaosbladeFile wraps the opened file. The attribute operations on the file carry no
+// request context, so they are refused and the path based operations of ChaosbladeFs,
+// which receive the caller, are used instead.
+type chaosbladeFile struct {
+	nodefs.File
+	name string
+}
+
+func newChaosbladeFile(file nodefs.File, name string) nodefs.File {
+	return &chaosbladeFile{File: file, name: name}
+}
+
+func (f *chaosbladeFile) InnerFile() nodefs.File {
+	return f.File
+}
+
+func (f *chaosbladeFile) String() string {
+	return fmt.Sprintf("ChaosbladeFile{file=%s, name=%s}", f.File.String(), f.name)
+}
+
+func (f *chaosbladeFile) GetAttr(out *fuse.Attr) fuse.Status {
+	return fuse.ENOSYS
+}
+
+func (f *chaosbladeFile) Truncate(size uint64) fuse.Status {
+	return fuse.ENOSYS
+}
+
+func (f *chaosbladeFile) Chown(uid uint32, gid uint32) fuse.Status {
+	return fuse.ENOSYS
+}
+
+func (f *chaosbladeFile) Chmod(perms uint32) fuse.Status {
+	return fuse.ENOSYS
+}
+
+func (f *chaosbladeFile) Utimens(atime *time.Time, mtime *time.Time) fuse.Status {
+	return fuse.ENOSYS
+}
+
+// chaosbladeRawFs intercepts the file handle operations, whose requests carry the caller
+// but which reach the opened file without it, and the statfs which the path file system
+// receives without the caller.
+type chaosbladeRawFs struct {
+	fuse.RawFileSystem
+	fs *ChaosbladeFs
+}
+
+func (r *chaosbladeRawFs) String() string {
+	return r.fs.String()
+}
+
+func (r *chaosbladeRawFs) Open(input *fuse.OpenIn, out *fuse.OpenOut) fuse.Status {
+	code := r.RawFileSystem.Open(input, out)
+	r.registerHandle(&input.Context, out.Fh, code)
+	return code
+}
+
+func (r *chaosbladeRawFs) Create(input *fuse.CreateIn, name string, out *fuse.CreateOut) fuse.Status {
+	code := r.RawFileSystem.Create(input, name, out)
+	r.registerHandle(&input.Context, out.Fh, code)
+	return code
+}
+
+func (r *chaosbladeRawFs) registerHandle(context *fuse.Context, fh uint64, code fuse.Status) {
+	name, ok := r.fs.opening.LoadAndDelete(context)
+	if ok && code.Ok() {
+		r.fs.handles.Store(fh, name)
+	}
+}
+
+// injectFault injects the fault of the method into the file handle operation
+func (r *chaosbladeRawFs) injectFault(fh uint64, method string, caller *fuse.Context) error {
+	name, ok := r.fs.handles.Load(fh)
+	if !ok {
+		return nil
+	}
+	return r.fs.hook.doInjectFault(name.(string), method, caller)
+}
+
+func (r *chaosbladeRawFs) Read(input *fuse.ReadIn, buf []byte) (fuse.ReadResult, fuse.Status) {
+	if err := r.injectFault(input.Fh, "read", &input.Context); err != nil {
+		return nil, fuse.ToStatus(err)
+	}
+	return r.RawFileSystem.Read(input, buf)
+}
+
+func (r *chaosbladeRawFs) Write(input *fuse.WriteIn, data []byte) (uint32, fuse.Status) {
+	if err := r.injectFault(input.Fh, "write", &input.Context); err != nil {
+		return 0, fuse.ToStatus(err)
+	}
+	return r.RawFileSystem.Write(input, data)
+}
+
+func (r *chaosbladeRawFs) GetLk(input *fuse.LkIn, out *fuse.LkOut) fuse.Status {
+	if err := r.injectFault(input.Fh, "getlk", &input.Context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	return r.RawFileSystem.GetLk(input, out)
+}
+
+func (r *chaosbladeRawFs) SetLk(input *fuse.LkIn) fuse.Status {
+	if err := r.injectFault(input.Fh, "setlk", &input.Context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	return r.RawFileSystem.SetLk(input)
+}
+
+func (r *chaosbladeRawFs) SetLkw(input *fuse.LkIn) fuse.Status {
+	if err := r.injectFault(input.Fh, "setlkw", &input.Context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	return r.RawFileSystem.SetLkw(input)
+}
+
+func (r *chaosbladeRawFs) Flush(input *fuse.FlushIn) fuse.Status {
+	if err := r.injectFault(input.Fh, "flush", &input.Context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	return r.RawFileSystem.Flush(input)
+}
+
+func (r *chaosbladeRawFs) Release(input *fuse.ReleaseIn) {
+	_ = r.injectFault(input.Fh, "release", &input.Context)
+	r.fs.handles.Delete(input.Fh)
+	r.RawFileSystem.Release(input)
+}
+
+func (r *chaosbladeRawFs) Fsync(input *fuse.FsyncIn) fuse.Status {
+	if err := r.injectFault(input.Fh, "fsync", &input.Context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	return r.RawFileSystem.Fsync(input)
+}
+
+func (r *chaosbladeRawFs) Fallocate(input *fuse.FallocateIn) fuse.Status {
+	if err := r.injectFault(input.Fh, "allocate", &input.Context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	return r.RawFileSystem.Fallocate(input)
+}
+
+// StatFs injects the fault after the file system is queried, which has no side effect, so that
+// the name of the node is known without holding the lock during the injected delay.
+func (r *chaosbladeRawFs) StatFs(input *fuse.InHeader, out *fuse.StatfsOut) fuse.Status {
+	r.fs.statFsLock.Lock()
+	code := r.RawFileSystem.StatFs(input, out)
+	name := r.fs.statFsName
+	r.fs.statFsLock.Unlock()
+	if err := r.fs.hook.doInjectFault(name, "statfs", &input.Context); err != nil {
+		return fuse.ToStatus(err)
+	}
+	return code
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hookfs
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/hanwen/go-fuse/fuse"
+)
+
+func Test_chaosbladeRawFs_callerOfEachOperation(t *testing.T) {
+	fs := NewChaosbladeFs("/original", "/mount")
+	raw := &chaosbladeRawFs{RawFileSystem: fuse.NewDefaultRawFileSystem(), fs: fs}
+	fs.handles.Store(uint64(1), "data/file")
+	injectFaultCache.Store("read", newInjectRule(&InjectMessage{Methods: []string{"read"}, Errno: uint32(syscall.EIO), Uids: []uint32{1000}}))
+	defer injectFaultCache.Delete("read")
+
+	read := func(uid uint32) fuse.Status {
+		input := &fuse.ReadIn{InHeader: fuse.InHeader{Context: fuse.Context{Owner: fuse.Owner{Uid: uid}}}, Fh: 1}
+		_, code := raw.Read(input, nil)
+		return code
+	}
+	// the file is read by different callers through the same handle
+	if code := read(1000); code != fuse.EIO {
+		t.Errorf("Read() by the selected caller = %v, want %v", code, fuse.EIO)
+	}
+	if code := read(1001); code != fuse.ENOSYS {
+		t.Errorf("Read() by another caller = %v, want the inner status %v", code, fuse.ENOSYS)
+	}
+
+	raw.Release(&fuse.ReleaseIn{Fh: 1})
+	if _, ok := fs.handles.Load(uint64(1)); ok {
+		t.Errorf("the handle is not removed after release")
+	}
+}
+
+func Test_chaosbladeRawFs_registerHandle(t *testing.T) {
+	fs := NewChaosbladeFs("/original", "/mount")
+	raw := &chaosbladeRawFs{RawFileSystem: fuse.NewDefaultRawFileSystem(), fs: fs}
+	opened := &fuse.Context{Pid: 1}
+	failed := &fuse.Context{Pid: 2}
+	fs.opening.Store(opened, "data/opened")
+	fs.opening.Store(failed, "data/failed")
+
+	raw.registerHandle(opened, 1, fuse.OK)
+	raw.registerHandle(failed, 2, fuse.ENOENT)
+	if name, ok := fs.handles.Load(uint64(1)); !ok || name != "data/opened" {
+		t.Errorf("handle 1 = %v, want data/opened", name)
+	}
+	if _, ok := fs.handles.Load(uint64(2)); ok {
+		t.Errorf("the handle of the failed open is registered")
+	}
+	for _, context := range []*fuse.Context{opened, failed} {
+		if _, ok := fs.opening.Load(context); ok {
+			t.Errorf("the opening name of %v is not removed", context)
+		}
+	}
+}
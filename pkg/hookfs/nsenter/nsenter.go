@@ -0,0 +1,24 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package nsenter enters the mount namespace of the target process before the go runtime starts,
+// setns(CLONE_NEWNS) is not allowed once the process is multithreaded. The fuse device is opened
+// before entering, so the fuse mount works even if the target has no /dev/fuse.
+package nsenter
+
+// TargetPidEnv is the env of the target process id, the process enters the mount namespace
+// of the target on start if set
+const TargetPidEnv = "_CHAOSBLADE_FUSE_TARGET_PID"
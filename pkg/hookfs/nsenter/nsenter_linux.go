@@ -0,0 +1,71 @@
+//go:build linux && cgo
+
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package nsenter
+
+/*
+#cgo CFLAGS: -Wall
+#define _GNU_SOURCE
+#include <errno.h>
+#include <fcntl.h>
+#include <sched.h>
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+#include <unistd.h>
+
+static int fuse_dev_fd = -1;
+
+__attribute__((constructor)) static void enter_mount_namespace(void) {
+	const char *pid = getenv("_CHAOSBLADE_FUSE_TARGET_PID");
+	if (pid == NULL || *pid == '\0') {
+		return;
+	}
+	fuse_dev_fd = open("/dev/fuse", O_RDWR | O_CLOEXEC);
+	if (fuse_dev_fd < 0) {
+		fprintf(stderr, "open /dev/fuse failed, %s\n", strerror(errno));
+		exit(1);
+	}
+	char path[64];
+	snprintf(path, sizeof(path), "/proc/%s/ns/mnt", pid);
+	int fd = open(path, O_RDONLY | O_CLOEXEC);
+	if (fd < 0) {
+		fprintf(stderr, "open %s failed, %s\n", path, strerror(errno));
+		exit(1);
+	}
+	if (setns(fd, CLONE_NEWNS) < 0) {
+		fprintf(stderr, "enter mount namespace of %s failed, %s\n", pid, strerror(errno));
+		exit(1);
+	}
+	close(fd);
+	if (chdir("/") < 0) {
+		fprintf(stderr, "chdir failed, %s\n", strerror(errno));
+		exit(1);
+	}
+}
+
+static int get_fuse_dev_fd(void) {
+	return fuse_dev_fd;
+}
+*/
+import "C"
+
+// FuseDevFd returns the fd of /dev/fuse opened before entering, -1 if not entered
+func FuseDevFd() int {
+	return int(C.get_fuse_dev_fd())
+}
@@ -21,9 +21,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"path"
 	"sync"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaosblade-operator/pkg/auth"
 )
 
 var injectFaultCache sync.Map
@@ -39,10 +44,20 @@ type InjectMessage struct {
 	Percent uint32   `json:"percent"`
 	Random  bool     `json:"random"`
 	Errno   uint32   `json:"errno"`
+	// Pids, ProcessNames and Uids scope the fault to the matched callers, all callers are affected if empty
+	Pids         []uint32 `json:"pids,omitempty"`
+	ProcessNames []string `json:"processNames,omitempty"`
+	Uids         []uint32 `json:"uids,omitempty"`
+	// TTL is the seconds the rule lives after the injection or the last lease renewal, never expires if 0
+	TTL uint32 `json:"ttl,omitempty"`
 }
 
 type ChaosbladeHookServer struct {
 	addr string
+	// authDir contains the token and the serving certificate, the server serves plain http if empty
+	authDir string
+	// shutdown stops the fuse server, the shutdown path is not served if nil
+	shutdown func()
 }
 
 func NewChaosbladeHookServer(addr string) *ChaosbladeHookServer {
@@ -51,20 +66,57 @@ func NewChaosbladeHookServer(addr string) *ChaosbladeHookServer {
 	}
 }
 
+// NewSecureChaosbladeHookServer returns the server serving https and requiring the bearer token in the authDir
+func NewSecureChaosbladeHookServer(addr, authDir string) *ChaosbladeHookServer {
+	return &ChaosbladeHookServer{
+		addr:    addr,
+		authDir: authDir,
+	}
+}
+
+// SetShutdownHandler serves the shutdown path which calls the handler, the fuse container
+// attached to a running pod exits by it after the experiment is destroyed
+func (s *ChaosbladeHookServer) SetShutdownHandler(shutdown func()) {
+	s.shutdown = shutdown
+}
+
 func (s *ChaosbladeHookServer) Start(stop context.Context) error {
+	controlHandler := func(handler http.HandlerFunc) http.Handler { return handler }
+	if s.authDir != "" {
+		token, err := auth.ReadToken(path.Join(s.authDir, AuthTokenKey))
+		if err != nil {
+			return err
+		}
+		controlHandler = func(handler http.HandlerFunc) http.Handler { return auth.RequireToken(token, handler) }
+	}
 	mux := http.NewServeMux()
-	mux.HandleFunc(InjectPath, s.InjectHandler)
-	mux.HandleFunc(RecoverPath, s.RecoverHandler)
-	errCh := make(chan error)
+	mux.Handle(InjectPath, controlHandler(s.InjectHandler))
+	mux.Handle(RecoverPath, controlHandler(s.RecoverHandler))
+	mux.Handle(StatusPath, controlHandler(s.StatusHandler))
+	mux.Handle(LeasePath, controlHandler(s.LeaseHandler))
+	if s.shutdown != nil {
+		mux.Handle(ShutdownPath, controlHandler(s.ShutdownHandler))
+	}
+	// the metrics expose the paths and the callers of the rules, so they require the token as well
+	mux.Handle(MetricsPath, controlHandler(promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{}).ServeHTTP))
+	errCh := make(chan error, 1)
 	server := &http.Server{
 		Addr:    s.addr,
 		Handler: mux,
 	}
 	go func() {
+		if s.authDir != "" {
+			errCh <- server.ListenAndServeTLS(path.Join(s.authDir, AuthCertKey), path.Join(s.authDir, AuthKeyKey))
+			return
+		}
 		errCh <- server.ListenAndServe()
 	}()
+	ticker := time.NewTicker(expireInterval)
+	defer ticker.Stop()
 	for {
 		select {
+		case now := <-ticker.C:
+			expireRules(now)
 		case <-stop.Done():
 			return server.Shutdown(context.Background())
 		case err := <-errCh:
@@ -83,8 +135,9 @@ func (s *ChaosbladeHookServer) InjectHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 	logrus.WithField("injectMsg", injectMsg).Infoln("Inject Fault")
+	rule := newInjectRule(&injectMsg)
 	for _, method := range injectMsg.Methods {
-		injectFaultCache.Store(method, &injectMsg)
+		injectFaultCache.Store(method, rule)
 	}
 	fmt.Fprintf(w, "success")
 }
@@ -96,3 +149,32 @@ func (s *ChaosbladeHookServer) RecoverHandler(w http.ResponseWriter, r *http.Req
 	}
 	fmt.Fprintf(w, "success")
 }
+
+// LeaseHandler extends the expiration of the active rules, the operator renews
+// the lease periodically while the experiment exists
+func (s *ChaosbladeHookServer) LeaseHandler(w http.ResponseWriter, r *http.Request) {
+	renewRules(time.Now())
+	fmt.Fprintf(w, "success")
+}
+
+// ShutdownHandler recovers all faults and stops the fuse server
+func (s *ChaosbladeHookServer) ShutdownHandler(w http.ResponseWriter, r *http.Request) {
+	logrus.Infoln("shutdown the fuse server")
+	for _, method := range defaultHookPoints {
+		injectFaultCache.Delete(method)
+	}
+	fmt.Fprintf(w, "success")
+	s.shutdown()
+}
+
+// StatusHandler returns the active rules with their hit counters
+func (s *ChaosbladeHookServer) StatusHandler(w http.ResponseWriter, r *http.Request) {
+	statuses := make([]RuleStatus, 0)
+	for _, rule := range activeRules() {
+		statuses = append(statuses, rule.status())
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(statuses); err != nil {
+		logrus.WithError(err).Errorln("Encode rule statuses failed")
+	}
+}
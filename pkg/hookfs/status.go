@@ -0,0 +1,161 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hookfs
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// injectRule is an active rule with its hit counters
+type injectRule struct {
+	*InjectMessage
+	matched  uint64
+	injected uint64
+	// unix nano of the last injection
+	lastHit int64
+	// unix nano when the rule expires, never expires if 0
+	expireAt int64
+}
+
+func newInjectRule(injectMsg *InjectMessage) *injectRule {
+	rule := &injectRule{InjectMessage: injectMsg}
+	rule.renew(time.Now())
+	return rule
+}
+
+// renew extends the expiration of the rule by its ttl
+func (r *injectRule) renew(now time.Time) {
+	if r.TTL == 0 {
+		return
+	}
+	atomic.StoreInt64(&r.expireAt, now.Add(time.Duration(r.TTL)*time.Second).UnixNano())
+}
+
+func (r *injectRule) expired(now time.Time) bool {
+	expireAt := atomic.LoadInt64(&r.expireAt)
+	return expireAt > 0 && now.UnixNano() >= expireAt
+}
+
+func (r *injectRule) recordMatched(method string) {
+	atomic.AddUint64(&r.matched, 1)
+	faultMatchedTotal.WithLabelValues(method).Inc()
+}
+
+func (r *injectRule) recordInjected(method string) {
+	atomic.AddUint64(&r.injected, 1)
+	atomic.StoreInt64(&r.lastHit, time.Now().UnixNano())
+	faultInjectedTotal.WithLabelValues(method).Inc()
+}
+
+func (r *injectRule) status() RuleStatus {
+	status := RuleStatus{
+		InjectMessage: *r.InjectMessage,
+		Matched:       atomic.LoadUint64(&r.matched),
+		Injected:      atomic.LoadUint64(&r.injected),
+	}
+	if lastHit := atomic.LoadInt64(&r.lastHit); lastHit > 0 {
+		t := time.Unix(0, lastHit)
+		status.LastHit = &t
+	}
+	if expireAt := atomic.LoadInt64(&r.expireAt); expireAt > 0 {
+		t := time.Unix(0, expireAt)
+		status.ExpireAt = &t
+	}
+	return status
+}
+
+// RuleStatus is the statistics of an active rule. Matched counts the calls selected by
+// the path and caller filters, Injected counts the calls the fault was actually applied to.
+type RuleStatus struct {
+	InjectMessage
+	Matched  uint64     `json:"matched"`
+	Injected uint64     `json:"injected"`
+	LastHit  *time.Time `json:"lastHit,omitempty"`
+	ExpireAt *time.Time `json:"expireAt,omitempty"`
+}
+
+// activeRules returns the distinct rules in the cache, a rule is shared by all its methods
+func activeRules() []*injectRule {
+	rules := make([]*injectRule, 0)
+	seen := make(map[*injectRule]bool)
+	injectFaultCache.Range(func(key, value interface{}) bool {
+		rule, ok := value.(*injectRule)
+		if !ok || seen[rule] {
+			return true
+		}
+		seen[rule] = true
+		rules = append(rules, rule)
+		return true
+	})
+	return rules
+}
+
+// renewRules extends the expiration of all active rules
+func renewRules(now time.Time) {
+	for _, rule := range activeRules() {
+		rule.renew(now)
+	}
+}
+
+// expireRules removes the expired rules from the cache
+func expireRules(now time.Time) {
+	injectFaultCache.Range(func(key, value interface{}) bool {
+		rule, ok := value.(*injectRule)
+		if ok && rule.expired(now) {
+			if injectFaultCache.CompareAndDelete(key, value) {
+				logrus.WithField("method", key).WithField("injectMsg", rule.InjectMessage).
+					Infoln("Remove the expired fault")
+				faultExpiredTotal.WithLabelValues(key.(string)).Inc()
+			}
+		}
+		return true
+	})
+}
+
+var (
+	metricsRegistry = prometheus.NewRegistry()
+
+	faultMatchedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "chaosblade_fuse_fault_matched_total",
+		Help: "Total number of file system calls matched by the fault rules",
+	}, []string{"method"})
+
+	faultInjectedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "chaosblade_fuse_fault_injected_total",
+		Help: "Total number of file system calls the fault was injected into",
+	}, []string{"method"})
+
+	faultExpiredTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "chaosblade_fuse_fault_expired_total",
+		Help: "Total number of the fault rules removed because the ttl expired",
+	}, []string{"method"})
+
+	activeRulesGauge = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "chaosblade_fuse_active_rules",
+		Help: "Number of the active fault rules",
+	}, func() float64 {
+		return float64(len(activeRules()))
+	})
+)
+
+func init() {
+	metricsRegistry.MustRegister(faultMatchedTotal, faultInjectedTotal, faultExpiredTotal, activeRulesGauge)
+}
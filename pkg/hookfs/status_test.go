@@ -0,0 +1,51 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hookfs
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_expireRules(t *testing.T) {
+	now := time.Now()
+	expiring := newInjectRule(&InjectMessage{Methods: []string{"read"}, TTL: 10})
+	permanent := newInjectRule(&InjectMessage{Methods: []string{"write"}})
+	injectFaultCache.Store("read", expiring)
+	injectFaultCache.Store("write", permanent)
+	defer func() {
+		injectFaultCache.Delete("read")
+		injectFaultCache.Delete("write")
+	}()
+
+	expireRules(now.Add(5 * time.Second))
+	if _, ok := injectFaultCache.Load("read"); !ok {
+		t.Fatalf("the rule expired before the ttl")
+	}
+	renewRules(now.Add(5 * time.Second))
+	expireRules(now.Add(12 * time.Second))
+	if _, ok := injectFaultCache.Load("read"); !ok {
+		t.Fatalf("the renewed rule expired before the ttl")
+	}
+	expireRules(now.Add(16 * time.Second))
+	if _, ok := injectFaultCache.Load("read"); ok {
+		t.Errorf("the rule did not expire after the ttl")
+	}
+	if _, ok := injectFaultCache.Load("write"); !ok {
+		t.Errorf("the rule without ttl expired")
+	}
+}
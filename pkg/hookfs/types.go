@@ -16,6 +16,8 @@
 
 package hookfs
 
+import "time"
+
 var defaultHookPoints = []string{
 	"read",
 	"write",
@@ -49,6 +51,32 @@ var defaultHookPoints = []string{
 }
 
 var (
-	InjectPath  = "/inject"
-	RecoverPath = "/recover"
+	InjectPath   = "/inject"
+	RecoverPath  = "/recover"
+	StatusPath   = "/status"
+	LeasePath    = "/lease"
+	ShutdownPath = "/shutdown"
+	MetricsPath  = "/metrics"
+)
+
+// expireInterval is the interval to remove the expired rules
+const expireInterval = time.Second
+
+const (
+	// AuthServerName is the dns name in the serving certificate of the hook server
+	AuthServerName = "chaosblade-fuse"
+	// AuthTokenKey, AuthCertKey, AuthKeyKey and AuthCAKey are the file names in the auth directory,
+	// also the keys of the auth secret
+	AuthTokenKey = "token"
+	AuthCertKey  = "tls.crt"
+	AuthKeyKey   = "tls.key"
+	AuthCAKey    = "ca.crt"
+)
+
+// AuthTokenEnv, AuthCertEnv and AuthKeyEnv are the envs of the auth secret, used by the
+// ephemeral fuse container which can not mount the secret volume
+const (
+	AuthTokenEnv = "CHAOSBLADE_FUSE_TOKEN"
+	AuthCertEnv  = "CHAOSBLADE_FUSE_TLS_CRT"
+	AuthKeyEnv   = "CHAOSBLADE_FUSE_TLS_KEY"
 )
@@ -17,6 +17,8 @@
 package chaosblade
 
 import (
+	"time"
+
 	"github.com/spf13/pflag"
 
 	"github.com/chaosblade-io/chaosblade-operator/version"
@@ -29,6 +31,33 @@ var (
 	DaemonsetEnable     bool
 	RemoveBladeInterval string
 	DownloadUrl         string
+	// IOFaultTTL is the default ttl of the pod IO fault rules in the fuse sidecar
+	IOFaultTTL time.Duration
+	// IOFaultLeaseInterval is the interval to renew the lease of the pod IO fault rules
+	IOFaultLeaseInterval time.Duration
+	// JournalEnable records the injections in the journal configmaps to recover them after the operator restarts
+	JournalEnable bool
+	// StickyEnable watches the pods to inject the sticky experiments into the new pods
+	StickyEnable bool
+	// OrphanSweepInterval is the interval to destroy the experiments unknown by the chaosblades in the chaosblade tool pods
+	OrphanSweepInterval time.Duration
+	// OrphanGracePeriod is the min age of the unknown experiments to destroy
+	OrphanGracePeriod time.Duration
+	// SuccessPolicy is the default policy to judge the experiment successful by the targets
+	SuccessPolicy string
+	// RetryFailedTargets is the max times to inject the experiment again into the failed targets
+	RetryFailedTargets int
+	// RetryFailedBackoff is the wait before the first retry of the failed targets, doubled after each retry
+	RetryFailedBackoff time.Duration
+	// ExperimentParallelism is the default max number of the experiments of a chaosblade created concurrently
+	ExperimentParallelism int
+	// StartBarrierTimeout is the max wait of the experiments for the others to start together
+	StartBarrierTimeout time.Duration
+	// ExecConcurrency is the default max number of the targets of an experiment executed concurrently
+	ExecConcurrency int
+	// ExecQPS and ExecBurst are the default token bucket of the exec calls of an experiment, no limit if ExecQPS is 0
+	ExecQPS   float32
+	ExecBurst int
 )
 
 const (
@@ -74,6 +103,20 @@ func init() {
 	f.StringVar(&RemoveBladeInterval, "remove-blade-interval", DefaultRemoveBladeInterval, "Periodically clean up blade state is destroying, default value is 24h.")
 	f.StringVar(&DownloadUrl, "chaosblade-download-url", "", "The chaosblade downloaded address which works when the chaosblade is deployed in download mode.")
 	f.StringVar(&DaemonsetPodNamespace, "chaosblade-namespace", "chaosblade", "The chaosblade deployment namespace")
+	f.DurationVar(&IOFaultTTL, "io-fault-ttl", 0, "The default ttl of the pod IO fault in the fuse sidecar, the fault is removed if the lease is not renewed within the ttl. Never expires if 0.")
+	f.BoolVar(&JournalEnable, "journal-enable", false, "Record the injection intents and results in the journal configmaps before the chaosblade status is updated, the injections lost by the operator restart are adopted or destroyed on startup. Each target is written to the journal before and after the command, and the target fails if the journal is not written, so it is disabled by default.")
+	f.BoolVar(&StickyEnable, "sticky-enable", false, "Watch the pods to inject the experiments with the sticky flag into the new pods matching the selector, the pods are cached by the operator if enabled, default value is false.")
+	f.DurationVar(&OrphanSweepInterval, "orphan-sweep-interval", 0, "The interval to destroy the experiments in the chaosblade tool pods which are unknown by the chaosblades, such as the ones left by the force deleted chaosblades. The experiments created by hand in the chaosblade tool pods are destroyed too, so it is disabled by default. Disabled if 0, only works with daemonset-enable.")
+	f.DurationVar(&OrphanGracePeriod, "orphan-grace-period", 30*time.Minute, "The min age of the unknown experiments in the chaosblade tool pods to destroy, the experiments being created are not recorded in the chaosblades yet.")
+	f.StringVar(&SuccessPolicy, "success-policy", "all", "The default policy to judge the experiment successful by the targets, all, any or the min percent of the successful targets such as 80%. The chaosblade is PartiallyRunning if some targets or experiments failed.")
+	f.IntVar(&RetryFailedTargets, "retry-failed-targets", 0, "The max times to inject the experiment again into the failed targets of the PartiallyRunning or Error chaosblades, disabled if 0. The failed target may be injected if the result is lost, so it is disabled by default.")
+	f.DurationVar(&RetryFailedBackoff, "retry-failed-backoff", 30*time.Second, "The wait before the first retry of the failed targets, doubled after each retry.")
+	f.IntVar(&ExperimentParallelism, "experiment-parallelism", 4, "The default max number of the experiments of a chaosblade created concurrently, overridden by the parallelism of the chaosblade spec.")
+	f.DurationVar(&StartBarrierTimeout, "start-barrier-timeout", 5*time.Minute, "The max wait of the experiments of a chaosblade with the start barrier for the others to be ready to inject.")
+	f.IntVar(&ExecConcurrency, "exec-concurrency", 64, "The default max number of the targets of an experiment executed concurrently, overridden by the exec-concurrency flag of the experiment.")
+	f.Float32Var(&ExecQPS, "exec-qps", 0, "The default max exec calls per second of an experiment, overridden by the exec-qps flag of the experiment. No limit if 0.")
+	f.IntVar(&ExecBurst, "exec-burst", 10, "The default max burst of the exec calls of an experiment limited by exec-qps, overridden by the exec-burst flag of the experiment.")
+	f.DurationVar(&IOFaultLeaseInterval, "io-fault-lease-interval", 10*time.Second, "The interval to renew the lease of the pod IO fault while the experiment exists, must be less than the ttl.")
 }
 
 func FlagSet() *pflag.FlagSet {
@@ -19,6 +19,7 @@ package runtime
 import (
 	"github.com/spf13/pflag"
 
+	"github.com/chaosblade-io/chaosblade-operator/channel"
 	"github.com/chaosblade-io/chaosblade-operator/pkg/runtime/chaosblade"
 	"github.com/chaosblade-io/chaosblade-operator/pkg/runtime/product/aliyun"
 	_ "github.com/chaosblade-io/chaosblade-operator/pkg/runtime/product/community"
@@ -40,6 +41,7 @@ func init() {
 
 	flagSet.AddFlagSet(aliyun.FlagSet())
 	flagSet.AddFlagSet(chaosblade.FlagSet())
+	flagSet.AddFlagSet(channel.FlagSet())
 
 	initRuntimeData()
 }
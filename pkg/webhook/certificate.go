@@ -0,0 +1,264 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/chaosblade-io/chaosblade-operator/pkg/certs"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/runtime/chaosblade"
+)
+
+const (
+	// the keys of the webhook certificate secret, the ca-bundle.crt contains the current and the previous ca
+	// so that the serving certificate signed by either one is trusted during the rotation
+	caCertKey     = "ca.crt"
+	caKeyKey      = "ca.key"
+	caBundleKey   = "ca-bundle.crt"
+	servingKeyKey = corev1.TLSPrivateKeyKey
+	servingCrtKey = corev1.TLSCertKey
+
+	certificateResyncPeriod = time.Hour
+)
+
+// CertManager generates the self-signed ca and the serving certificate of the webhook server,
+// stores them in the CertSecret, writes them to the CertDir and patches the caBundle of the
+// MutatingWebhookConfiguration. Both are rotated before expiry. It runs on every replica
+// because each one serves the webhook from its own CertDir, the caBundle is always read from
+// the CertSecret so the replicas do not patch the stale one back after another rotates it.
+type CertManager struct {
+	Clientset kubernetes.Interface
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface
+func (m *CertManager) NeedLeaderElection() bool {
+	return false
+}
+
+// Start checks the certificates periodically until the context is done
+func (m *CertManager) Start(ctx context.Context) error {
+	ticker := time.NewTicker(certificateResyncPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if err := m.Ensure(ctx); err != nil {
+			logrus.WithError(err).WithField("secret", CertSecret).Errorln("ensure webhook certificates failed")
+		}
+	}
+}
+
+// Ensure creates or rotates the certificates in the secret, and applies them to the cert dir
+// and the webhook configuration. It must succeed before the webhook server starts.
+func (m *CertManager) Ensure(ctx context.Context) error {
+	if CertRotateBefore >= CertValidity {
+		return fmt.Errorf("the webhook cert rotate before %s must be less than the validity %s", CertRotateBefore, CertValidity)
+	}
+	var data map[string][]byte
+	err := retry.OnError(retry.DefaultBackoff, func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}, func() (err error) {
+		data, err = m.ensureSecret(ctx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	// the new ca is trusted before the serving certificate signed by it is served
+	patchErr := m.patchCABundle(ctx)
+	if err := writeCertDir(data); err != nil {
+		return err
+	}
+	return patchErr
+}
+
+func (m *CertManager) ensureSecret(ctx context.Context) (map[string][]byte, error) {
+	client := m.Clientset.CoreV1().Secrets(chaosblade.DaemonsetPodNamespace)
+	secret, err := client.Get(ctx, CertSecret, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		data, _, err := renewCertificates(nil, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		logrus.WithField("secret", CertSecret).Infoln("create webhook certificates")
+		_, err = client.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      CertSecret,
+				Namespace: chaosblade.DaemonsetPodNamespace,
+				Labels: map[string]string{
+					"app.kubernetes.io/managed-by": "chaosblade-operator",
+				},
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: data,
+		}, metav1.CreateOptions{})
+		return data, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, renewed, err := renewCertificates(secret.Data, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !renewed {
+		return data, nil
+	}
+	logrus.WithField("secret", CertSecret).Infoln("rotate webhook certificates")
+	secret.Data = data
+	_, err = client.Update(ctx, secret, metav1.UpdateOptions{})
+	return data, err
+}
+
+// readSecretCABundle returns the ca bundle in the CertSecret shared by the replicas, the ca-bundle.crt
+// written by the CertManager is preferred to the ca.crt. Returns nil if not exists
+func readSecretCABundle(ctx context.Context, clientset kubernetes.Interface) ([]byte, error) {
+	secret, err := clientset.CoreV1().Secrets(chaosblade.DaemonsetPodNamespace).Get(ctx, CertSecret, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get webhook certificate secret error, %v", err)
+	}
+	if caBundle := secret.Data[caBundleKey]; len(caBundle) > 0 {
+		return caBundle, nil
+	}
+	return secret.Data[caCertKey], nil
+}
+
+// renewCertificates returns the certificates with the ones expiring within CertRotateBefore regenerated,
+// the serving certificate is regenerated with the ca
+func renewCertificates(data map[string][]byte, now time.Time) (map[string][]byte, bool, error) {
+	renewed := make(map[string][]byte, len(data))
+	for key, value := range data {
+		renewed[key] = value
+	}
+	ca := &certs.KeyPair{Cert: data[caCertKey], Key: data[caKeyKey]}
+	caExpiring := len(ca.Key) == 0 || len(data[caBundleKey]) == 0 || expiring(ca.Cert, now)
+	if !caExpiring && !expiring(data[servingCrtKey], now) && len(data[servingKeyKey]) > 0 {
+		return renewed, false, nil
+	}
+	if caExpiring {
+		newCA, err := certs.GenerateCA(fmt.Sprintf("%s-ca", ServiceName), CertValidity)
+		if err != nil {
+			return nil, false, err
+		}
+		bundle := newCA.Cert
+		if notAfter, err := certs.NotAfter(ca.Cert); err == nil && notAfter.After(now) {
+			bundle = append(bytes.Clone(newCA.Cert), ca.Cert...)
+		}
+		renewed[caCertKey] = newCA.Cert
+		renewed[caKeyKey] = newCA.Key
+		renewed[caBundleKey] = bundle
+		ca = newCA
+	}
+	serviceHost := fmt.Sprintf("%s.%s", ServiceName, chaosblade.DaemonsetPodNamespace)
+	serving, err := certs.GenerateServingCert(ca, fmt.Sprintf("%s.svc", serviceHost),
+		[]string{ServiceName, serviceHost, fmt.Sprintf("%s.svc", serviceHost)}, CertValidity)
+	if err != nil {
+		return nil, false, err
+	}
+	renewed[servingCrtKey] = serving.Cert
+	renewed[servingKeyKey] = serving.Key
+	return renewed, true, nil
+}
+
+// expiring returns true if the certificate is invalid or expires within CertRotateBefore
+func expiring(certPEM []byte, now time.Time) bool {
+	notAfter, err := certs.NotAfter(certPEM)
+	if err != nil {
+		return true
+	}
+	return notAfter.Sub(now) < CertRotateBefore
+}
+
+// writeCertDir writes the changed certificates to the cert dir, the webhook server reloads them
+func writeCertDir(data map[string][]byte) error {
+	if err := os.MkdirAll(CertDir, 0755); err != nil {
+		return fmt.Errorf("create webhook cert dir error, %v", err)
+	}
+	// the key is written before the certificate, the pair is reloaded when the certificate changes
+	for _, key := range []string{caBundleKey, servingKeyKey, servingCrtKey} {
+		file := path.Join(CertDir, key)
+		if current, err := os.ReadFile(file); err == nil && bytes.Equal(current, data[key]) {
+			continue
+		}
+		tmp := file + ".tmp"
+		if err := os.WriteFile(tmp, data[key], 0600); err != nil {
+			return fmt.Errorf("write webhook certificate %s error, %v", key, err)
+		}
+		if err := os.Rename(tmp, file); err != nil {
+			return fmt.Errorf("write webhook certificate %s error, %v", key, err)
+		}
+	}
+	return nil
+}
+
+// patchCABundle updates the caBundle of the webhooks served by the operator to the one in the secret. The
+// secret is read after the configuration, so the update conflicts if another replica rotates the ca between.
+func (m *CertManager) patchCABundle(ctx context.Context) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		client := m.Clientset.AdmissionregistrationV1().MutatingWebhookConfigurations()
+		configuration, err := client.Get(ctx, ConfigurationName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			logrus.WithField("name", ConfigurationName).Debugln("mutating webhook configuration not found, skip patching the ca bundle")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		caBundle, err := readSecretCABundle(ctx, m.Clientset)
+		if err != nil {
+			return err
+		}
+		if len(caBundle) == 0 {
+			return nil
+		}
+		changed := false
+		for i, webhook := range configuration.Webhooks {
+			service := webhook.ClientConfig.Service
+			if service == nil || service.Name != ServiceName || service.Namespace != chaosblade.DaemonsetPodNamespace {
+				continue
+			}
+			if !bytes.Equal(webhook.ClientConfig.CABundle, caBundle) {
+				configuration.Webhooks[i].ClientConfig.CABundle = caBundle
+				changed = true
+			}
+		}
+		if !changed {
+			return nil
+		}
+		logrus.WithField("name", ConfigurationName).Infoln("patch the ca bundle of mutating webhook configuration")
+		_, err = client.Update(ctx, configuration, metav1.UpdateOptions{})
+		return err
+	})
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_renewCertificates(t *testing.T) {
+	CertValidity = 10 * time.Hour
+	CertRotateBefore = time.Hour
+	now := time.Now()
+
+	data, renewed, err := renewCertificates(nil, now)
+	if err != nil || !renewed {
+		t.Fatalf("generate certificates, renewed: %t, err: %v", renewed, err)
+	}
+	if !bytes.Equal(data[caBundleKey], data[caCertKey]) {
+		t.Errorf("unexpected ca bundle of the first ca")
+	}
+
+	current, renewed, err := renewCertificates(data, now.Add(8*time.Hour))
+	if err != nil || renewed {
+		t.Fatalf("valid certificates renewed: %t, err: %v", renewed, err)
+	}
+
+	rotated, renewed, err := renewCertificates(current, now.Add(9*time.Hour+time.Minute))
+	if err != nil || !renewed {
+		t.Fatalf("expiring certificates not renewed, err: %v", err)
+	}
+	if bytes.Equal(rotated[caCertKey], data[caCertKey]) || bytes.Equal(rotated[servingCrtKey], data[servingCrtKey]) {
+		t.Errorf("expected both the ca and the serving certificate rotated")
+	}
+	if !bytes.Equal(rotated[caBundleKey], append(bytes.Clone(rotated[caCertKey]), data[caCertKey]...)) {
+		t.Errorf("expected the previous ca kept in the ca bundle")
+	}
+
+	// the expired previous ca is dropped from the bundle
+	rotated, _, err = renewCertificates(map[string][]byte{caCertKey: data[caCertKey]}, now.Add(11*time.Hour))
+	if err != nil {
+		t.Fatalf("rotate certificates failed, %v", err)
+	}
+	if !bytes.Equal(rotated[caBundleKey], rotated[caCertKey]) {
+		t.Errorf("expected the expired ca removed from the ca bundle")
+	}
+}
+
+func TestCertManager_patchCABundle(t *testing.T) {
+	// the replica patches the ca rotated by another replica in the secret instead of its own one
+	clientset := fake.NewSimpleClientset(newCertSecret(map[string][]byte{caBundleKey: []byte("rotated")}))
+	configuration, err := newMutatingWebhookConfiguration([]byte("stale"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Create(
+		context.Background(), configuration, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	manager := &CertManager{Clientset: clientset}
+	if err := manager.patchCABundle(context.Background()); err != nil {
+		t.Fatalf("patchCABundle() error = %v", err)
+	}
+	got, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(
+		context.Background(), ConfigurationName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got.Webhooks[0].ClientConfig.CABundle) != "rotated" {
+		t.Errorf("ca bundle = %q, want rotated", got.Webhooks[0].ClientConfig.CABundle)
+	}
+}
@@ -0,0 +1,198 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/chaosblade-io/chaosblade-operator/pkg/runtime/chaosblade"
+)
+
+const (
+	// MutatingPodsPath is the path of the pod mutating webhook
+	MutatingPodsPath = "/mutating-pods"
+	// NamespaceOptInLabel enables the pod mutating webhook for the namespace if NamespaceOptIn is set
+	NamespaceOptInLabel = "chaosblade.io/fuse-injection"
+	NamespaceOptInValue = "enabled"
+
+	configurationResyncPeriod = 10 * time.Minute
+)
+
+// ConfigurationManager creates the MutatingWebhookConfiguration of the operator and keeps it updated
+type ConfigurationManager struct {
+	Clientset kubernetes.Interface
+}
+
+// Start applies the configuration on start and periodically until the context is done
+func (m *ConfigurationManager) Start(ctx context.Context) error {
+	ticker := time.NewTicker(configurationResyncPeriod)
+	defer ticker.Stop()
+	for {
+		if err := m.apply(ctx); err != nil {
+			logrus.WithError(err).WithField("name", ConfigurationName).Errorln("apply mutating webhook configuration failed")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *ConfigurationManager) apply(ctx context.Context) error {
+	client := m.Clientset.AdmissionregistrationV1().MutatingWebhookConfigurations()
+	current, err := client.Get(ctx, ConfigurationName, metav1.GetOptions{})
+	notFound := apierrors.IsNotFound(err)
+	if err != nil && !notFound {
+		return err
+	}
+	// the ca bundle is read after the configuration like the CertManager, so the update conflicts if the
+	// ca is rotated between
+	caBundle, err := readSecretCABundle(ctx, m.Clientset)
+	if err != nil {
+		return err
+	}
+	expected, err := newMutatingWebhookConfiguration(caBundle)
+	if err != nil {
+		return err
+	}
+	if notFound {
+		logrus.WithField("name", ConfigurationName).Infoln("create mutating webhook configuration")
+		_, err = client.Create(ctx, expected, metav1.CreateOptions{})
+		return err
+	}
+	// keep the ca bundle patched by others if the operator has no ca
+	if len(expected.Webhooks[0].ClientConfig.CABundle) == 0 {
+		for _, webhook := range current.Webhooks {
+			if webhook.Name == expected.Webhooks[0].Name {
+				expected.Webhooks[0].ClientConfig.CABundle = webhook.ClientConfig.CABundle
+			}
+		}
+	}
+	current.Labels = expected.Labels
+	current.Webhooks = expected.Webhooks
+	_, err = client.Update(ctx, current, metav1.UpdateOptions{})
+	return err
+}
+
+func newMutatingWebhookConfiguration(caBundle []byte) (*admissionregistrationv1.MutatingWebhookConfiguration, error) {
+	namespaceSelector, err := parseLabelSelector(NamespaceSelector)
+	if err != nil {
+		return nil, fmt.Errorf("illegal webhook namespace selector %s, %v", NamespaceSelector, err)
+	}
+	if NamespaceOptIn {
+		if namespaceSelector.MatchLabels == nil {
+			namespaceSelector.MatchLabels = map[string]string{}
+		}
+		namespaceSelector.MatchLabels[NamespaceOptInLabel] = NamespaceOptInValue
+	}
+	objectSelector, err := parseLabelSelector(ObjectSelector)
+	if err != nil {
+		return nil, fmt.Errorf("illegal webhook object selector %s, %v", ObjectSelector, err)
+	}
+	webhookPath := MutatingPodsPath
+	failurePolicy := admissionregistrationv1.Ignore
+	sideEffects := admissionregistrationv1.SideEffectClassNoneOnDryRun
+	scope := admissionregistrationv1.NamespacedScope
+	timeoutSeconds := int32(10)
+	return &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ConfigurationName,
+			Labels: map[string]string{
+				"app": "chaosblade-operator",
+			},
+		},
+		Webhooks: []admissionregistrationv1.MutatingWebhook{
+			{
+				Name: fmt.Sprintf("%s.%s.svc", ServiceName, chaosblade.DaemonsetPodNamespace),
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{
+						Name:      ServiceName,
+						Namespace: chaosblade.DaemonsetPodNamespace,
+						Path:      &webhookPath,
+					},
+					CABundle: caBundle,
+				},
+				Rules: []admissionregistrationv1.RuleWithOperations{
+					{
+						// the sidecar can only be injected on creation
+						Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+						Rule: admissionregistrationv1.Rule{
+							APIGroups:   []string{""},
+							APIVersions: []string{"v1"},
+							Resources:   []string{"pods"},
+							Scope:       &scope,
+						},
+					},
+				},
+				FailurePolicy:           &failurePolicy,
+				SideEffects:             &sideEffects,
+				NamespaceSelector:       namespaceSelector,
+				ObjectSelector:          objectSelector,
+				TimeoutSeconds:          &timeoutSeconds,
+				AdmissionReviewVersions: []string{"v1", "v1beta1"},
+			},
+		},
+	}, nil
+}
+
+// parseLabelSelector parses the selector like metav1.ParseToLabelSelector, and supports the != operator
+// which is converted to the NotIn expression
+func parseLabelSelector(selector string) (*metav1.LabelSelector, error) {
+	requirements, err := labels.ParseToRequirements(selector)
+	if err != nil {
+		return nil, err
+	}
+	labelSelector := &metav1.LabelSelector{
+		MatchLabels:      map[string]string{},
+		MatchExpressions: []metav1.LabelSelectorRequirement{},
+	}
+	for _, requirement := range requirements {
+		var operator metav1.LabelSelectorOperator
+		switch requirement.Operator() {
+		case selection.Equals, selection.DoubleEquals:
+			labelSelector.MatchLabels[requirement.Key()] = requirement.Values().List()[0]
+			continue
+		case selection.In:
+			operator = metav1.LabelSelectorOpIn
+		case selection.NotIn, selection.NotEquals:
+			operator = metav1.LabelSelectorOpNotIn
+		case selection.Exists:
+			operator = metav1.LabelSelectorOpExists
+		case selection.DoesNotExist:
+			operator = metav1.LabelSelectorOpDoesNotExist
+		default:
+			return nil, fmt.Errorf("%q is not a valid label selector operator", requirement.Operator())
+		}
+		labelSelector.MatchExpressions = append(labelSelector.MatchExpressions, metav1.LabelSelectorRequirement{
+			Key:      requirement.Key(),
+			Operator: operator,
+			Values:   requirement.Values().List(),
+		})
+	}
+	return labelSelector, nil
+}
@@ -0,0 +1,216 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/chaosblade-io/chaosblade-operator/pkg/runtime/chaosblade"
+)
+
+func Test_newMutatingWebhookConfiguration(t *testing.T) {
+	defer func(namespaceSelector, objectSelector string, optIn bool) {
+		NamespaceSelector, ObjectSelector, NamespaceOptIn = namespaceSelector, objectSelector, optIn
+	}(NamespaceSelector, ObjectSelector, NamespaceOptIn)
+	tests := []struct {
+		name                  string
+		namespaceSelector     string
+		objectSelector        string
+		namespaceOptIn        bool
+		wantNamespaceSelector *metav1.LabelSelector
+		wantObjectSelector    *metav1.LabelSelector
+		wantErr               bool
+	}{
+		{
+			name:                  "no selector",
+			wantNamespaceSelector: &metav1.LabelSelector{},
+			wantObjectSelector:    &metav1.LabelSelector{},
+		},
+		{
+			name:              "set based selectors",
+			namespaceSelector: "env in (test,dev),!production",
+			objectSelector:    "app notin (db),tier",
+			wantNamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "env", Operator: metav1.LabelSelectorOpIn, Values: []string{"dev", "test"}},
+				{Key: "production", Operator: metav1.LabelSelectorOpDoesNotExist},
+			}},
+			wantObjectSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "app", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"db"}},
+				{Key: "tier", Operator: metav1.LabelSelectorOpExists},
+			}},
+		},
+		{
+			name:                  "selectors",
+			namespaceSelector:     "env=test",
+			objectSelector:        "app!=db",
+			wantNamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "test"}},
+			wantObjectSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "app", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"db"}},
+			}},
+		},
+		{
+			name:              "namespace opt in",
+			namespaceSelector: "env=test",
+			namespaceOptIn:    true,
+			wantNamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{
+				"env": "test", NamespaceOptInLabel: NamespaceOptInValue,
+			}},
+			wantObjectSelector: &metav1.LabelSelector{},
+		},
+		{name: "illegal namespace selector", namespaceSelector: "env in (", wantErr: true},
+		{name: "illegal object selector", objectSelector: "app in (", wantErr: true},
+		{name: "unsupported operator", objectSelector: "replicas>1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			NamespaceSelector, ObjectSelector, NamespaceOptIn = tt.namespaceSelector, tt.objectSelector, tt.namespaceOptIn
+			got, err := newMutatingWebhookConfiguration([]byte("ca"))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newMutatingWebhookConfiguration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			webhook := got.Webhooks[0]
+			if !equalSelector(webhook.NamespaceSelector, tt.wantNamespaceSelector) {
+				t.Errorf("namespace selector = %+v, want %+v", webhook.NamespaceSelector, tt.wantNamespaceSelector)
+			}
+			if !equalSelector(webhook.ObjectSelector, tt.wantObjectSelector) {
+				t.Errorf("object selector = %+v, want %+v", webhook.ObjectSelector, tt.wantObjectSelector)
+			}
+			if string(webhook.ClientConfig.CABundle) != "ca" {
+				t.Errorf("ca bundle = %q, want ca", webhook.ClientConfig.CABundle)
+			}
+			if len(webhook.Rules) != 1 || !reflect.DeepEqual(webhook.Rules[0].Operations,
+				[]admissionregistrationv1.OperationType{admissionregistrationv1.Create}) {
+				t.Errorf("unexpected rules %+v", webhook.Rules)
+			}
+		})
+	}
+}
+
+func equalSelector(got, want *metav1.LabelSelector) bool {
+	gotSelector, err := metav1.LabelSelectorAsSelector(got)
+	if err != nil {
+		return false
+	}
+	wantSelector, err := metav1.LabelSelectorAsSelector(want)
+	if err != nil {
+		return false
+	}
+	return gotSelector.String() == wantSelector.String()
+}
+
+func TestConfigurationManager_apply(t *testing.T) {
+	defer func(certDir string) { CertDir = certDir }(CertDir)
+	tests := []struct {
+		name         string
+		caBundle     []byte
+		existing     []byte
+		wantCABundle []byte
+	}{
+		{name: "create with the ca", caBundle: []byte("ca")},
+		{name: "create without the ca"},
+		{name: "update the ca", caBundle: []byte("ca"), existing: []byte("old"), wantCABundle: []byte("ca")},
+		{name: "keep the ca patched by others", existing: []byte("others"), wantCABundle: []byte("others")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the stale ca in the cert dir of the replica must be ignored
+			CertDir = t.TempDir()
+			if err := os.WriteFile(path.Join(CertDir, caBundleKey), []byte("stale"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			clientset := fake.NewSimpleClientset()
+			if tt.caBundle != nil {
+				clientset = fake.NewSimpleClientset(newCertSecret(map[string][]byte{caBundleKey: tt.caBundle}))
+			}
+			if tt.existing != nil {
+				existing, err := newMutatingWebhookConfiguration(tt.existing)
+				if err != nil {
+					t.Fatal(err)
+				}
+				existing.Webhooks[0].FailurePolicy = nil
+				if _, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Create(
+					context.Background(), existing, metav1.CreateOptions{}); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				tt.wantCABundle = tt.caBundle
+			}
+			manager := &ConfigurationManager{Clientset: clientset}
+			if err := manager.apply(context.Background()); err != nil {
+				t.Fatalf("apply() error = %v", err)
+			}
+			got, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(
+				context.Background(), ConfigurationName, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("the configuration is not applied, %v", err)
+			}
+			if !bytes.Equal(got.Webhooks[0].ClientConfig.CABundle, tt.wantCABundle) {
+				t.Errorf("ca bundle = %q, want %q", got.Webhooks[0].ClientConfig.CABundle, tt.wantCABundle)
+			}
+			if got.Webhooks[0].FailurePolicy == nil || *got.Webhooks[0].FailurePolicy != admissionregistrationv1.Ignore {
+				t.Errorf("the webhook is not updated to the expected one")
+			}
+		})
+	}
+}
+
+func Test_readSecretCABundle(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string][]byte
+		want []byte
+	}{
+		{name: "no secret", want: nil},
+		{name: "ca certificate", data: map[string][]byte{caCertKey: []byte("ca")}, want: []byte("ca")},
+		{name: "ca bundle preferred", data: map[string][]byte{caCertKey: []byte("ca"), caBundleKey: []byte("bundle")}, want: []byte("bundle")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset()
+			if tt.data != nil {
+				clientset = fake.NewSimpleClientset(newCertSecret(tt.data))
+			}
+			got, err := readSecretCABundle(context.Background(), clientset)
+			if err != nil {
+				t.Fatalf("readSecretCABundle() error = %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("readSecretCABundle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func newCertSecret(data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: CertSecret, Namespace: chaosblade.DaemonsetPodNamespace},
+		Data:       data,
+	}
+}
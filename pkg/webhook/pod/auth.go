@@ -0,0 +1,137 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pod
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/chaosblade-io/chaosblade-operator/pkg/auth"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/certs"
+	"github.com/chaosblade-io/chaosblade-operator/pkg/hookfs"
+)
+
+// FuseAuthEnable enables the token authentication and tls of the fuse sidecar server. It is disabled
+// by default, the sidecars injected before keep serving plain http and are still reachable after enabling it
+var FuseAuthEnable bool
+
+const (
+	// FuseAuthSecretName is the secret contains the token and the certificates of the fuse sidecar in each namespace
+	FuseAuthSecretName = "chaosblade-fuse-auth"
+	FuseAuthVolumeName = "chaosblade-fuse-auth"
+	FuseAuthMountPath  = "/etc/chaosblade/fuse-auth"
+
+	fuseAuthValidity = 10 * 365 * 24 * time.Hour
+)
+
+// HasFuseAuth returns true if the fuse sidecar of the pod mounts the auth secret
+func HasFuseAuth(pod *corev1.Pod) bool {
+	for _, container := range pod.Spec.Containers {
+		if container.Name != SidecarName {
+			continue
+		}
+		for _, volumeMount := range container.VolumeMounts {
+			if volumeMount.Name == FuseAuthVolumeName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// injectFuseAuth mounts the auth secret into the sidecar
+func injectFuseAuth(pod *corev1.Pod, sidecar *corev1.Container) {
+	sidecar.Args = append(sidecar.Args, fmt.Sprintf("--auth-dir=%s", FuseAuthMountPath))
+	sidecar.VolumeMounts = append(sidecar.VolumeMounts, corev1.VolumeMount{
+		Name:      FuseAuthVolumeName,
+		MountPath: FuseAuthMountPath,
+		ReadOnly:  true,
+	})
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Name == FuseAuthVolumeName {
+			return
+		}
+	}
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: FuseAuthVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: FuseAuthSecretName,
+			},
+		},
+	})
+}
+
+// EnsureFuseAuthSecret creates the auth secret in the namespace if not exists. The secret is read
+// by the clientset rather than the cached client, which would list and watch the secrets of the cluster.
+func EnsureFuseAuthSecret(ctx context.Context, clientset kubernetes.Interface, namespace string) error {
+	client := clientset.CoreV1().Secrets(namespace)
+	_, err := client.Get(ctx, FuseAuthSecretName, metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+	secret, err := newFuseAuthSecret(namespace)
+	if err != nil {
+		return err
+	}
+	logrus.WithField("namespace", namespace).Infoln("Create fuse auth secret")
+	if _, err := client.Create(ctx, secret, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+func newFuseAuthSecret(namespace string) (*corev1.Secret, error) {
+	token, err := auth.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+	ca, err := certs.GenerateCA("chaosblade-fuse-ca", fuseAuthValidity)
+	if err != nil {
+		return nil, err
+	}
+	serving, err := certs.GenerateServingCert(ca, hookfs.AuthServerName, []string{hookfs.AuthServerName}, fuseAuthValidity)
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      FuseAuthSecretName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "chaosblade-operator",
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			hookfs.AuthTokenKey: []byte(token),
+			hookfs.AuthCertKey:  serving.Cert,
+			hookfs.AuthKeyKey:   serving.Key,
+			hookfs.AuthCAKey:    ca.Cert,
+		},
+	}, nil
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pod
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/chaosblade-io/chaosblade-operator/pkg/hookfs"
+)
+
+func TestEnsureFuseAuthSecret(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	ctx := context.Background()
+	if err := EnsureFuseAuthSecret(ctx, clientset, "default"); err != nil {
+		t.Fatalf("EnsureFuseAuthSecret() error = %v", err)
+	}
+	secret, err := clientset.CoreV1().Secrets("default").Get(ctx, FuseAuthSecretName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("the fuse auth secret is not created, %v", err)
+	}
+	for _, key := range []string{hookfs.AuthTokenKey, hookfs.AuthCertKey, hookfs.AuthKeyKey, hookfs.AuthCAKey} {
+		if len(secret.Data[key]) == 0 {
+			t.Errorf("the fuse auth secret has no %s", key)
+		}
+	}
+
+	// the existing secret is kept, the running sidecars trust its token
+	if err := EnsureFuseAuthSecret(ctx, clientset, "default"); err != nil {
+		t.Fatalf("EnsureFuseAuthSecret() on the existing secret error = %v", err)
+	}
+	current, _ := clientset.CoreV1().Secrets("default").Get(ctx, FuseAuthSecretName, metav1.GetOptions{})
+	if string(current.Data[hookfs.AuthTokenKey]) != string(secret.Data[hookfs.AuthTokenKey]) {
+		t.Errorf("the token of the existing secret is regenerated")
+	}
+}
+
+func Test_injectFuseAuth(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app"}},
+		},
+	}
+	if HasFuseAuth(pod) {
+		t.Fatalf("HasFuseAuth() of the pod without sidecar = true")
+	}
+	sidecar := corev1.Container{Name: SidecarName}
+	injectFuseAuth(pod, &sidecar)
+	injectFuseAuth(pod, &corev1.Container{Name: SidecarName})
+	pod.Spec.Containers = append(pod.Spec.Containers, sidecar)
+	if !HasFuseAuth(pod) {
+		t.Errorf("HasFuseAuth() of the injected pod = false")
+	}
+	if len(pod.Spec.Volumes) != 1 || pod.Spec.Volumes[0].Secret.SecretName != FuseAuthSecretName {
+		t.Errorf("unexpected volumes %+v", pod.Spec.Volumes)
+	}
+	if len(sidecar.Args) != 1 || sidecar.Args[0] != "--auth-dir="+FuseAuthMountPath {
+		t.Errorf("unexpected sidecar args %v", sidecar.Args)
+	}
+}
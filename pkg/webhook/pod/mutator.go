@@ -22,10 +22,11 @@ import (
 	"fmt"
 	"net/http"
 	"path"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -43,10 +44,30 @@ const (
 	FuseServerPortName = "fuse-port"
 )
 
+const (
+	// InjectContainerAnnotation is the container whose volumes are mounted over, the first container by default
+	InjectContainerAnnotation = "chaosblade/inject-container"
+	// InjectVolumeAnnotation is the comma separated volume names of the container
+	InjectVolumeAnnotation = "chaosblade/inject-volume"
+	// InjectVolumeSubPathAnnotation is the comma separated sub paths of the volumes,
+	// a single sub path is used for all volumes and the empty value mounts over the whole volumes
+	InjectVolumeSubPathAnnotation = "chaosblade/inject-volume-subpath"
+)
+
 // PodMutator set default values for pod
 type Mutator struct {
-	client  client.Client
-	decoder admission.Decoder
+	client    client.Client
+	clientset kubernetes.Interface
+	decoder   admission.Decoder
+}
+
+// NewMutator returns the pod mutator with the clients and the decoder
+func NewMutator(client client.Client, clientset kubernetes.Interface, decoder admission.Decoder) *Mutator {
+	return &Mutator{
+		client:    client,
+		clientset: clientset,
+		decoder:   decoder,
+	}
 }
 
 func (v *Mutator) Handle(ctx context.Context, req admission.Request) admission.Response {
@@ -61,6 +82,13 @@ func (v *Mutator) Handle(ctx context.Context, req admission.Request) admission.R
 		logrus.WithError(err).Errorln("mutate pod failed")
 		return admission.Errored(http.StatusInternalServerError, err)
 	}
+	mutateToolkitFn(patchPod)
+	if HasFuseAuth(patchPod) && (req.DryRun == nil || !*req.DryRun) {
+		if err := EnsureFuseAuthSecret(ctx, v.clientset, req.Namespace); err != nil {
+			logrus.WithError(err).WithField("namespace", req.Namespace).Errorln("ensure fuse auth secret failed")
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+	}
 	originalBytes, err := json.Marshal(pod)
 	if err != nil {
 		logrus.WithError(err).Errorln("Marshal original pod err")
@@ -78,14 +106,14 @@ func (v *Mutator) mutatePodsFn(pod *corev1.Pod) error {
 	if pod.Annotations == nil {
 		return nil
 	}
-	injectVolumeName, ok := pod.Annotations["chaosblade/inject-volume"]
+	injectVolumeNames, ok := pod.Annotations[InjectVolumeAnnotation]
 	if !ok {
-		logrus.WithField("name", pod.Name).Infoln("pod has no chaosblade/inject-volume annotation")
+		logrus.WithField("name", pod.Name).Infof("pod has no %s annotation", InjectVolumeAnnotation)
 		return nil
 	}
-	injectSubPath, ok := pod.Annotations["chaosblade/inject-volume-subpath"]
+	injectSubPaths, ok := pod.Annotations[InjectVolumeSubPathAnnotation]
 	if !ok {
-		logrus.WithField("name", pod.Name).Infoln("pod has no chaosblade/inject-volume annotation")
+		logrus.WithField("name", pod.Name).Infof("pod has no %s annotation", InjectVolumeSubPathAnnotation)
 		return nil
 	}
 
@@ -96,83 +124,119 @@ func (v *Mutator) mutatePodsFn(pod *corev1.Pod) error {
 		}
 	}
 
-	var targetVolumeMount corev1.VolumeMount
-	// inject sidecar for the first container
-	for _, volumeMount := range pod.Spec.Containers[0].VolumeMounts {
-		if volumeMount.Name == injectVolumeName {
-			if volumeMount.MountPropagation == nil {
-				return fmt.Errorf("target volume mount propagation must be HostToContainer or Bidirectional")
-			}
-			if *(volumeMount.MountPropagation) != corev1.MountPropagationHostToContainer &&
-				*(volumeMount.MountPropagation) != corev1.MountPropagationBidirectional {
-				return fmt.Errorf("target volume mount propagation is not support")
-			}
-			targetVolumeMount = volumeMount
-			mountPropagation := corev1.MountPropagationBidirectional
-			targetVolumeMount.MountPropagation = &mountPropagation
-		}
+	template := GetSidecarTemplate()
+	mountPropagation := template.mountPropagation()
+	targetContainer, err := getTargetContainer(pod)
+	if err != nil {
+		return err
 	}
-
-	if targetVolumeMount.Name == "" {
-		return fmt.Errorf("pod has no volume mount %s", injectVolumeName)
+	volumeNames := splitAnnotation(injectVolumeNames)
+	subPaths := splitAnnotation(injectSubPaths)
+	if len(subPaths) > 1 && len(subPaths) != len(volumeNames) {
+		return fmt.Errorf("the count of %s must be 0, 1 or equal to the count of %s",
+			InjectVolumeSubPathAnnotation, InjectVolumeAnnotation)
 	}
 
-	privileged := true
-	runAsUser := int64(0) // root
-	mountPoint := path.Join(targetVolumeMount.MountPath, injectSubPath)
-	original := path.Join(targetVolumeMount.MountPath, fmt.Sprintf("fuse-%s", injectSubPath))
-	logrus.WithFields(logrus.Fields{
-		"mountPoint": mountPoint,
-		"mountPath":  targetVolumeMount.MountPath,
-		"podName":    pod.Name,
-	}).Infof("Get matched pod")
-	if mountPoint == targetVolumeMount.MountPath {
-		original = path.Join(path.Dir(targetVolumeMount.MountPath),
-			fmt.Sprintf("fuse-%s", path.Base(targetVolumeMount.MountPath)))
+	volumeMounts := make([]corev1.VolumeMount, 0)
+	mountArgs := make([]string, 0)
+	for i, volumeName := range volumeNames {
+		// the empty sub path mounts over the whole volume
+		subPath := ""
+		switch {
+		case len(subPaths) == 1:
+			subPath = subPaths[0]
+		case len(subPaths) > 1:
+			subPath = subPaths[i]
+		}
+		targetVolumeMount, err := getTargetVolumeMount(targetContainer, volumeName, mountPropagation)
+		if err != nil {
+			return err
+		}
+		mountPoint := path.Join(targetVolumeMount.MountPath, subPath)
+		original := path.Join(targetVolumeMount.MountPath, fmt.Sprintf("fuse-%s", subPath))
+		logrus.WithFields(logrus.Fields{
+			"mountPoint": mountPoint,
+			"mountPath":  targetVolumeMount.MountPath,
+			"podName":    pod.Name,
+			"container":  targetContainer.Name,
+		}).Infof("Get matched pod")
+		if mountPoint == targetVolumeMount.MountPath {
+			original = path.Join(path.Dir(targetVolumeMount.MountPath),
+				fmt.Sprintf("fuse-%s", path.Base(targetVolumeMount.MountPath)))
+		}
+		volumeMounts = append(volumeMounts, targetVolumeMount)
+		mountArgs = append(mountArgs, fmt.Sprintf("--mount=%s:%s", original, mountPoint))
 	}
-	sidecar := corev1.Container{
-		Name:            SidecarName,
-		Image:           GetSidecarImage(),
-		ImagePullPolicy: corev1.PullAlways,
-		Command: []string{
-			"/opt/chaosblade/bin/chaos_fuse",
-		},
 
-		Args: []string{
+	sidecar := corev1.Container{
+		Name: SidecarName,
+		Args: append([]string{
 			fmt.Sprintf("--address=:%d", FuseServerPort),
-			fmt.Sprintf("--mountpoint=%s", mountPoint),
-			fmt.Sprintf("--original=%s", original),
-		},
-		Resources: corev1.ResourceRequirements{
-			Requests: corev1.ResourceList{
-				corev1.ResourceCPU:    resource.MustParse("100m"),
-				corev1.ResourceMemory: resource.MustParse("50Mi"),
-			},
-			Limits: corev1.ResourceList{
-				corev1.ResourceCPU:    resource.MustParse("100m"),
-				corev1.ResourceMemory: resource.MustParse("50Mi"),
-			},
-		},
+		}, mountArgs...),
 		Ports: []corev1.ContainerPort{
 			{
 				Name:          FuseServerPortName,
 				ContainerPort: FuseServerPort,
 			},
 		},
-		SecurityContext: &corev1.SecurityContext{
-			Privileged: &privileged,
-			RunAsUser:  &runAsUser,
-		},
-		VolumeMounts: []corev1.VolumeMount{
-			targetVolumeMount,
-		},
+		VolumeMounts: volumeMounts,
 	}
-	containers := []corev1.Container{}
-	containers = append(containers, sidecar, pod.Spec.Containers[0])
-	pod.Spec.Containers = containers
+	template.apply(pod, &sidecar)
+	if FuseAuthEnable {
+		injectFuseAuth(pod, &sidecar)
+	}
+	// the sidecar goes first so the fuse is mounted before the other containers start
+	pod.Spec.Containers = append([]corev1.Container{sidecar}, pod.Spec.Containers...)
 	return nil
 }
 
+// getTargetContainer returns the container named by the inject-container annotation, the first container by default
+func getTargetContainer(pod *corev1.Pod) (*corev1.Container, error) {
+	if len(pod.Spec.Containers) == 0 {
+		return nil, fmt.Errorf("pod has no container")
+	}
+	containerName := strings.TrimSpace(pod.Annotations[InjectContainerAnnotation])
+	if containerName == "" {
+		return &pod.Spec.Containers[0], nil
+	}
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == containerName {
+			return &pod.Spec.Containers[i], nil
+		}
+	}
+	return nil, fmt.Errorf("pod has no container %s", containerName)
+}
+
+// getTargetVolumeMount returns the volume mount of the container, which is mounted by the sidecar with the propagation
+func getTargetVolumeMount(container *corev1.Container, volumeName string,
+	mountPropagation corev1.MountPropagationMode) (corev1.VolumeMount, error) {
+	for _, volumeMount := range container.VolumeMounts {
+		if volumeMount.Name != volumeName {
+			continue
+		}
+		if volumeMount.MountPropagation == nil {
+			return corev1.VolumeMount{}, fmt.Errorf("target volume mount propagation must be HostToContainer or Bidirectional")
+		}
+		if *(volumeMount.MountPropagation) != corev1.MountPropagationHostToContainer &&
+			*(volumeMount.MountPropagation) != corev1.MountPropagationBidirectional {
+			return corev1.VolumeMount{}, fmt.Errorf("target volume mount propagation is not support")
+		}
+		volumeMount.MountPropagation = &mountPropagation
+		return volumeMount, nil
+	}
+	return corev1.VolumeMount{}, fmt.Errorf("pod has no volume mount %s", volumeName)
+}
+
+func splitAnnotation(value string) []string {
+	values := make([]string, 0)
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
 // InjectClient injects the client.
 func (v *Mutator) InjectClient(c client.Client) error {
 	v.client = c
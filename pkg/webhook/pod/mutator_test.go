@@ -149,3 +149,330 @@ func Test_mutatePodsFn(t *testing.T) {
 		}
 	}
 }
+
+func Test_mutatePodsFnWithTargetContainer(t *testing.T) {
+	hostToContainer := v1.MountPropagationHostToContainer
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "pod-4",
+			Annotations: map[string]string{
+				InjectContainerAnnotation:     "app",
+				InjectVolumeAnnotation:        "data,logs",
+				InjectVolumeSubPathAnnotation: "db,app",
+			},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name:  "proxy",
+					Image: "proxy",
+				},
+				{
+					Name:  "app",
+					Image: "app",
+					VolumeMounts: []v1.VolumeMount{
+						{
+							Name:             "data",
+							MountPath:        "/data",
+							MountPropagation: &hostToContainer,
+						},
+						{
+							Name:             "logs",
+							MountPath:        "/var/log",
+							MountPropagation: &hostToContainer,
+						},
+					},
+				},
+			},
+		},
+	}
+	if err := (&Mutator{}).mutatePodsFn(pod); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(pod.Spec.Containers) != 3 {
+		t.Fatalf("unexpected container count %d, expected 3", len(pod.Spec.Containers))
+	}
+	sidecar := pod.Spec.Containers[0]
+	if sidecar.Name != SidecarName || pod.Spec.Containers[1].Name != "proxy" || pod.Spec.Containers[2].Name != "app" {
+		t.Errorf("unexpected containers %v", pod.Spec.Containers)
+	}
+	expectedArgs := []string{
+		"--mount=/data/fuse-db:/data/db",
+		"--mount=/var/log/fuse-app:/var/log/app",
+	}
+	for _, arg := range expectedArgs {
+		found := false
+		for _, sidecarArg := range sidecar.Args {
+			if sidecarArg == arg {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("sidecar args %v has no %s", sidecar.Args, arg)
+		}
+	}
+	if len(sidecar.VolumeMounts) != 2 {
+		t.Errorf("unexpected sidecar volume mounts %v", sidecar.VolumeMounts)
+	}
+}
+
+func Test_mutatePodsFnWithoutSubPath(t *testing.T) {
+	hostToContainer := v1.MountPropagationHostToContainer
+	newPod := func(subPaths string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pod-5",
+				Annotations: map[string]string{
+					InjectVolumeAnnotation:        "data,logs",
+					InjectVolumeSubPathAnnotation: subPaths,
+				},
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{
+						Name:  "app",
+						Image: "app",
+						VolumeMounts: []v1.VolumeMount{
+							{
+								Name:             "data",
+								MountPath:        "/data",
+								MountPropagation: &hostToContainer,
+							},
+							{
+								Name:             "logs",
+								MountPath:        "/var/log",
+								MountPropagation: &hostToContainer,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name         string
+		subPaths     string
+		expectedArgs []string
+		wantErr      bool
+	}{
+		{
+			name:         "empty sub path",
+			subPaths:     "",
+			expectedArgs: []string{"--mount=/fuse-data:/data", "--mount=/var/fuse-log:/var/log"},
+		},
+		{
+			name:         "single sub path",
+			subPaths:     "db",
+			expectedArgs: []string{"--mount=/data/fuse-db:/data/db", "--mount=/var/log/fuse-db:/var/log/db"},
+		},
+		{
+			name:     "sub path count mismatched",
+			subPaths: "db,app,cache",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := newPod(tt.subPaths)
+			err := (&Mutator{}).mutatePodsFn(pod)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("mutatePodsFn() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			sidecar := pod.Spec.Containers[0]
+			if sidecar.Name != SidecarName {
+				t.Fatalf("unexpected containers %v", pod.Spec.Containers)
+			}
+			for _, arg := range tt.expectedArgs {
+				found := false
+				for _, sidecarArg := range sidecar.Args {
+					if sidecarArg == arg {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("sidecar args %v has no %s", sidecar.Args, arg)
+				}
+			}
+		})
+	}
+}
+
+func Test_mutatePodsFnWithSidecarTemplate(t *testing.T) {
+	template, err := ParseSidecarTemplate(`
+imagePullPolicy: IfNotPresent
+imagePullSecrets:
+  - name: registry
+securityContext:
+  privileged: true
+  runAsUser: 0
+fuseDevice: true
+`)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	sidecarTemplate.Store(template)
+	defer sidecarTemplate.Store(nil)
+
+	hostToContainer := v1.MountPropagationHostToContainer
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "pod-5",
+			Annotations: map[string]string{
+				InjectVolumeAnnotation:        "data",
+				InjectVolumeSubPathAnnotation: "db",
+			},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name:  "app",
+					Image: "app",
+					VolumeMounts: []v1.VolumeMount{
+						{
+							Name:             "data",
+							MountPath:        "/data",
+							MountPropagation: &hostToContainer,
+						},
+					},
+				},
+			},
+		},
+	}
+	if err := (&Mutator{}).mutatePodsFn(pod); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	sidecar := pod.Spec.Containers[0]
+	if sidecar.ImagePullPolicy != v1.PullIfNotPresent {
+		t.Errorf("unexpected image pull policy %s", sidecar.ImagePullPolicy)
+	}
+	if len(sidecar.Command) != 1 || sidecar.Command[0] != "/opt/chaosblade/bin/chaos_fuse" {
+		t.Errorf("unexpected command %v", sidecar.Command)
+	}
+	if sidecar.Resources.Limits.Cpu().String() != "100m" {
+		t.Errorf("unexpected resources %v", sidecar.Resources)
+	}
+	if len(pod.Spec.ImagePullSecrets) != 1 || pod.Spec.ImagePullSecrets[0].Name != "registry" {
+		t.Errorf("unexpected image pull secrets %v", pod.Spec.ImagePullSecrets)
+	}
+	if *sidecar.VolumeMounts[0].MountPropagation != v1.MountPropagationBidirectional {
+		t.Errorf("the sidecar must mount bidirectionally")
+	}
+	if len(sidecar.VolumeMounts) != 2 || sidecar.VolumeMounts[1].MountPath != "/dev/fuse" {
+		t.Errorf("unexpected volume mounts %v", sidecar.VolumeMounts)
+	}
+}
+
+func TestParseSidecarTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "default security context", data: "imagePullPolicy: IfNotPresent"},
+		{name: "privileged", data: "securityContext:\n  privileged: true\n  runAsUser: 0"},
+		{name: "capabilities only", data: "securityContext:\n  capabilities:\n    add: [\"SYS_ADMIN\"]\nfuseDevice: true"},
+		{name: "privileged disabled", data: "securityContext:\n  privileged: false"},
+		{name: "unknown field", data: "unknown: true", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseSidecarTemplate(tt.data); (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSidecarTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_mutatePodsFnWithCapabilitiesTemplate(t *testing.T) {
+	template, err := ParseSidecarTemplate(`
+securityContext:
+  capabilities:
+    add: ["SYS_ADMIN"]
+fuseDevice: true
+`)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	sidecarTemplate.Store(template)
+	defer sidecarTemplate.Store(nil)
+
+	hostToContainer := v1.MountPropagationHostToContainer
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "pod-7",
+			Annotations: map[string]string{
+				InjectVolumeAnnotation:        "data",
+				InjectVolumeSubPathAnnotation: "db",
+			},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name:         "app",
+					Image:        "app",
+					VolumeMounts: []v1.VolumeMount{{Name: "data", MountPath: "/data", MountPropagation: &hostToContainer}},
+				},
+			},
+		},
+	}
+	if err := (&Mutator{}).mutatePodsFn(pod); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	sidecar := pod.Spec.Containers[0]
+	if sidecar.SecurityContext.Privileged != nil || len(sidecar.SecurityContext.Capabilities.Add) != 1 {
+		t.Errorf("unexpected security context %v", sidecar.SecurityContext)
+	}
+	// the bidirectional propagation is rejected for the containers which are not privileged
+	if *sidecar.VolumeMounts[0].MountPropagation != v1.MountPropagationHostToContainer {
+		t.Errorf("unexpected mount propagation %s", *sidecar.VolumeMounts[0].MountPropagation)
+	}
+	if len(sidecar.VolumeMounts) != 2 || sidecar.VolumeMounts[1].MountPath != "/dev/fuse" {
+		t.Errorf("unexpected volume mounts %v", sidecar.VolumeMounts)
+	}
+}
+
+func Test_mutateToolkitFn(t *testing.T) {
+	ToolkitInjectionEnable = true
+	defer func() { ToolkitInjectionEnable = false }()
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "pod-6",
+			Labels: map[string]string{
+				ToolkitInjectionLabel: ToolkitInjectionValue,
+			},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Name: "app", Image: "app"},
+				{
+					Name:  "opt",
+					Image: "opt",
+					VolumeMounts: []v1.VolumeMount{
+						{Name: "opt", MountPath: "/opt/chaosblade/"},
+					},
+				},
+			},
+		},
+	}
+	mutateToolkitFn(pod)
+	mutateToolkitFn(pod)
+	if len(pod.Spec.InitContainers) != 1 || pod.Spec.InitContainers[0].Name != ToolkitContainerName {
+		t.Fatalf("unexpected init containers %v", pod.Spec.InitContainers)
+	}
+	if len(pod.Spec.Volumes) != 1 || pod.Spec.Volumes[0].EmptyDir == nil {
+		t.Errorf("unexpected volumes %v", pod.Spec.Volumes)
+	}
+	if !HasToolkit(pod, "app", "/opt/chaosblade") {
+		t.Errorf("expected the toolkit mounted in the app container")
+	}
+	if HasToolkit(pod, "app", "/home/chaosblade") {
+		t.Errorf("the toolkit is not at the chaosblade path")
+	}
+	if HasToolkit(pod, "opt", "/opt/chaosblade") {
+		t.Errorf("the toolkit must not be mounted over the existing volume")
+	}
+}
@@ -0,0 +1,258 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pod
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	// SidecarConfigMap is the name of the configmap contains the sidecar template, the default template is used if empty
+	SidecarConfigMap string
+	// SidecarConfigMapNamespace is the namespace of the configmap
+	SidecarConfigMapNamespace string
+)
+
+const (
+	// SidecarTemplateKey is the key of the sidecar template in the configmap
+	SidecarTemplateKey = "sidecar.yaml"
+
+	fuseDeviceVolumeName = "chaosblade-fuse-device"
+	fuseDevicePath       = "/dev/fuse"
+)
+
+// SidecarTemplate is the customizable part of the fuse sidecar, the fields not set use the defaults
+type SidecarTemplate struct {
+	// Image overrides the fuse-sidecar-image flag
+	Image            string                        `json:"image,omitempty"`
+	ImagePullPolicy  corev1.PullPolicy             `json:"imagePullPolicy,omitempty"`
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// Command is the chaos_fuse command in the image
+	Command   []string                     `json:"command,omitempty"`
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// SecurityContext is privileged by default. The Pod Security compatible sidecar adds the SYS_ADMIN
+	// capability with the FuseDevice instead, but the bidirectional mount propagation is only allowed for
+	// the privileged containers, so it mounts the volumes with the HostToContainer propagation and the fuse
+	// is visible in the sidecar only, not in the other containers of the pod.
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+	// FuseDevice mounts /dev/fuse of the host into the sidecar, for the sidecars which are not privileged
+	// or the runtimes which do not expose the host devices to the privileged containers.
+	FuseDevice bool `json:"fuseDevice,omitempty"`
+}
+
+var sidecarTemplate atomic.Pointer[SidecarTemplate]
+
+// DefaultSidecarTemplate returns the template used if the configmap is not set
+func DefaultSidecarTemplate() *SidecarTemplate {
+	privileged := true
+	runAsUser := int64(0) // root
+	return &SidecarTemplate{
+		ImagePullPolicy: corev1.PullAlways,
+		Command: []string{
+			"/opt/chaosblade/bin/chaos_fuse",
+		},
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("50Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("50Mi"),
+			},
+		},
+		SecurityContext: &corev1.SecurityContext{
+			Privileged: &privileged,
+			RunAsUser:  &runAsUser,
+		},
+	}
+}
+
+// GetSidecarTemplate returns the template loaded from the configmap, or the default template
+func GetSidecarTemplate() *SidecarTemplate {
+	if template := sidecarTemplate.Load(); template != nil {
+		return template
+	}
+	return DefaultSidecarTemplate()
+}
+
+// ParseSidecarTemplate parses the template in yaml, the fields not set are filled with the defaults
+func ParseSidecarTemplate(data string) (*SidecarTemplate, error) {
+	template := &SidecarTemplate{}
+	if err := yaml.UnmarshalStrict([]byte(data), template); err != nil {
+		return nil, fmt.Errorf("parse sidecar template error, %v", err)
+	}
+	defaultTemplate := DefaultSidecarTemplate()
+	if template.ImagePullPolicy == "" {
+		template.ImagePullPolicy = defaultTemplate.ImagePullPolicy
+	}
+	if len(template.Command) == 0 {
+		template.Command = defaultTemplate.Command
+	}
+	if template.Resources == nil {
+		template.Resources = defaultTemplate.Resources
+	}
+	if template.SecurityContext == nil {
+		template.SecurityContext = defaultTemplate.SecurityContext
+	}
+	return template, nil
+}
+
+func (t *SidecarTemplate) privileged() bool {
+	return t.SecurityContext != nil && t.SecurityContext.Privileged != nil && *t.SecurityContext.Privileged
+}
+
+// mountPropagation returns the propagation of the volumes mounted by the sidecar, the bidirectional one
+// propagates the fuse mount to the other containers but requires the privileged sidecar
+func (t *SidecarTemplate) mountPropagation() corev1.MountPropagationMode {
+	if t.privileged() {
+		return corev1.MountPropagationBidirectional
+	}
+	return corev1.MountPropagationHostToContainer
+}
+
+// apply sets the template to the sidecar and the pod
+func (t *SidecarTemplate) apply(pod *corev1.Pod, sidecar *corev1.Container) {
+	sidecar.Image = GetSidecarImage()
+	if t.Image != "" {
+		sidecar.Image = t.Image
+	}
+	sidecar.ImagePullPolicy = t.ImagePullPolicy
+	sidecar.Command = append([]string{}, t.Command...)
+	if t.Resources != nil {
+		sidecar.Resources = *t.Resources.DeepCopy()
+	}
+	sidecar.SecurityContext = t.SecurityContext.DeepCopy()
+	for _, secret := range t.ImagePullSecrets {
+		if !containsLocalObjectReference(pod.Spec.ImagePullSecrets, secret) {
+			pod.Spec.ImagePullSecrets = append(pod.Spec.ImagePullSecrets, secret)
+		}
+	}
+	if t.FuseDevice {
+		hostPathType := corev1.HostPathCharDev
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name: fuseDeviceVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: fuseDevicePath,
+					Type: &hostPathType,
+				},
+			},
+		})
+		sidecar.VolumeMounts = append(sidecar.VolumeMounts, corev1.VolumeMount{
+			Name:      fuseDeviceVolumeName,
+			MountPath: fuseDevicePath,
+		})
+	}
+}
+
+func containsLocalObjectReference(references []corev1.LocalObjectReference, reference corev1.LocalObjectReference) bool {
+	for _, r := range references {
+		if r.Name == reference.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// SidecarTemplateWatcher watches the configmap and reloads the template, it runs in every
+// replica because the webhook server does not require the leader election
+type SidecarTemplateWatcher struct {
+	Clientset kubernetes.Interface
+}
+
+func (w *SidecarTemplateWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Start watches the configmap and reloads the template until the context is done
+func (w *SidecarTemplateWatcher) Start(ctx context.Context) error {
+	if SidecarConfigMap == "" {
+		logrus.Infoln("fuse sidecar configmap is not set, use the default sidecar template")
+		return nil
+	}
+	factory := informers.NewSharedInformerFactoryWithOptions(w.Clientset, 10*time.Minute,
+		informers.WithNamespace(SidecarConfigMapNamespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", SidecarConfigMap).String()
+		}))
+	informer := factory.Core().V1().ConfigMaps().Informer()
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			loadSidecarTemplate(obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			loadSidecarTemplate(newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			logrus.WithField("configmap", SidecarConfigMap).Warningln("fuse sidecar configmap is deleted, use the default sidecar template")
+			sidecarTemplate.Store(nil)
+		},
+	}); err != nil {
+		return err
+	}
+	logrus.WithFields(logrus.Fields{
+		"namespace": SidecarConfigMapNamespace,
+		"configmap": SidecarConfigMap,
+	}).Infoln("start watching fuse sidecar configmap")
+	factory.Start(ctx.Done())
+	<-ctx.Done()
+	factory.Shutdown()
+	return nil
+}
+
+// loadSidecarTemplate keeps the last valid template if the template in the configmap is illegal
+func loadSidecarTemplate(obj interface{}) {
+	configMap, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		return
+	}
+	logFields := logrus.WithFields(logrus.Fields{
+		"namespace":       configMap.Namespace,
+		"configmap":       configMap.Name,
+		"resourceVersion": configMap.ResourceVersion,
+	})
+	data, ok := configMap.Data[SidecarTemplateKey]
+	if !ok {
+		logFields.Warningf("fuse sidecar configmap has no %s, use the default sidecar template", SidecarTemplateKey)
+		sidecarTemplate.Store(nil)
+		return
+	}
+	template, err := ParseSidecarTemplate(data)
+	if err != nil {
+		logFields.WithError(err).Errorln("illegal fuse sidecar template, keep the last template")
+		return
+	}
+	if !template.privileged() {
+		logFields.Warningln("fuse sidecar template is not privileged, the fuse mount is not propagated to the other containers")
+	}
+	sidecarTemplate.Store(template)
+	logFields.Infoln("fuse sidecar template is reloaded")
+}
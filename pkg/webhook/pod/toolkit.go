@@ -0,0 +1,129 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pod
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/chaosblade-io/chaosblade-operator/pkg/runtime/chaosblade"
+)
+
+var (
+	// ToolkitInjectionEnable preinstalls the chaosblade toolkit in the pods labeled with the ToolkitInjectionLabel
+	ToolkitInjectionEnable bool
+	ToolkitImage           string
+)
+
+const (
+	// ToolkitInjectionLabel enables the toolkit injection for the pod if ToolkitInjectionEnable is set
+	ToolkitInjectionLabel = "chaosblade.io/toolkit-injection"
+	ToolkitInjectionValue = "enabled"
+	// ToolkitContainerName is the init container which copies the toolkit to the shared volume
+	ToolkitContainerName = "chaosblade-toolkit"
+	ToolkitVolumeName    = "chaosblade-toolkit"
+	// ToolkitPath is where the toolkit is mounted in the containers, the same as the default chaosblade path
+	// so the experiments without the chaosblade-path flag use it directly
+	ToolkitPath = chaosblade.OperatorChaosBladePath
+
+	toolkitInitMountPath = "/home"
+)
+
+// HasToolkit returns true if the toolkit is preinstalled at the chaosblade path of the container
+func HasToolkit(pod *corev1.Pod, containerName, chaosbladePath string) bool {
+	for _, container := range pod.Spec.Containers {
+		if container.Name != containerName {
+			continue
+		}
+		for _, volumeMount := range container.VolumeMounts {
+			if volumeMount.Name == ToolkitVolumeName && path.Clean(volumeMount.MountPath) == path.Clean(chaosbladePath) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mutateToolkitFn adds the init container which copies the toolkit to an emptyDir volume,
+// and mounts the volume at the ToolkitPath of the containers
+func mutateToolkitFn(pod *corev1.Pod) {
+	if !ToolkitInjectionEnable || pod.Labels[ToolkitInjectionLabel] != ToolkitInjectionValue {
+		return
+	}
+	for _, container := range pod.Spec.InitContainers {
+		if container.Name == ToolkitContainerName {
+			logrus.WithField("name", pod.Name).Infoln("toolkit has been injected")
+			return
+		}
+	}
+	// the same layout as the chaosblade-tool image, the toolkit is copied to the chaosblade sub path
+	subPath := path.Base(chaosblade.OperatorChaosBladePath)
+	initContainer := corev1.Container{
+		Name:            ToolkitContainerName,
+		Image:           GetToolkitImage(),
+		ImagePullPolicy: corev1.PullPolicy(chaosblade.PullPolicy),
+		Command:         []string{"cp", "-R", chaosblade.OperatorChaosBladePath, toolkitInitMountPath},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      ToolkitVolumeName,
+				MountPath: toolkitInitMountPath,
+			},
+		},
+	}
+	pod.Spec.InitContainers = append([]corev1.Container{initContainer}, pod.Spec.InitContainers...)
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: ToolkitVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+ContainerLoop:
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		if container.Name == SidecarName {
+			continue
+		}
+		for _, volumeMount := range container.VolumeMounts {
+			if path.Clean(volumeMount.MountPath) == ToolkitPath {
+				logrus.WithFields(logrus.Fields{
+					"name":      pod.Name,
+					"container": container.Name,
+				}).Warningf("the %s is mounted by other volume, skip mounting the toolkit", ToolkitPath)
+				continue ContainerLoop
+			}
+		}
+		// blade writes its data file in the toolkit directory, so the volume is writable
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      ToolkitVolumeName,
+			MountPath: ToolkitPath,
+			SubPath:   subPath,
+		})
+	}
+}
+
+func GetToolkitImage() string {
+	if ToolkitImage != "" {
+		return ToolkitImage
+	}
+	if chaosblade.Constant != nil {
+		return fmt.Sprintf("%s:%s", chaosblade.Constant.ImageRepoFunc(), chaosblade.Version)
+	}
+	return fmt.Sprintf("%s:%s", chaosblade.ImageRepository, chaosblade.Version)
+}
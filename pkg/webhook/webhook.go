@@ -17,6 +17,8 @@
 package webhook
 
 import (
+	"time"
+
 	"github.com/spf13/pflag"
 
 	mutator "github.com/chaosblade-io/chaosblade-operator/pkg/webhook/pod"
@@ -25,6 +27,21 @@ import (
 var (
 	Port   int
 	Enable bool
+	// CertDir is the directory contains the serving certificate of the webhook server
+	CertDir string
+	// CertAuto generates and rotates the certificates of the webhook server by the operator
+	CertAuto         bool
+	CertSecret       string
+	CertValidity     time.Duration
+	CertRotateBefore time.Duration
+
+	// ManageConfiguration creates and updates the MutatingWebhookConfiguration by the operator
+	ManageConfiguration bool
+	ConfigurationName   string
+	ServiceName         string
+	NamespaceSelector   string
+	ObjectSelector      string
+	NamespaceOptIn      bool
 )
 
 var f *pflag.FlagSet
@@ -33,9 +50,25 @@ func init() {
 	f = pflag.NewFlagSet("webhook", pflag.ExitOnError)
 	f.StringVar(&mutator.SidecarImage, "fuse-sidecar-image", "", "Fuse sidecar image")
 	f.Int32Var(&mutator.FuseServerPort, "fuse-server-port", 65534, "Fuse server port")
+	f.StringVar(&mutator.SidecarConfigMap, "fuse-sidecar-configmap", "", "The configmap contains the fuse sidecar template in the sidecar.yaml key, watched and reloaded. The default template is used if empty")
+	f.StringVar(&mutator.SidecarConfigMapNamespace, "fuse-sidecar-configmap-namespace", "chaosblade", "The namespace of the fuse sidecar configmap")
+	f.BoolVar(&mutator.FuseAuthEnable, "fuse-auth-enable", false, "Whether to serve the fuse sidecar server with tls and require the token generated in the chaosblade-fuse-auth secret, the operator needs to create the secret in the namespaces of the pods")
+	f.BoolVar(&mutator.ToolkitInjectionEnable, "toolkit-injection-enable", false, "Whether to preinstall the chaosblade toolkit by an init container in the pods labeled with chaosblade.io/toolkit-injection=enabled, the experiments skip deploying the toolkit to them")
+	f.StringVar(&mutator.ToolkitImage, "toolkit-image", "", "The image of the toolkit init container, the chaosblade tool image by default")
 
 	f.IntVar(&Port, "webhook-port", 9443, "The port on which to serve HTTPS.")
 	f.BoolVar(&Enable, "webhook-enable", false, "Whether to enable webhook")
+	f.StringVar(&CertDir, "webhook-cert-dir", "/tmp/k8s-webhook-server/serving-certs", "The directory contains the tls.crt, tls.key and ca.crt of the webhook server")
+	f.BoolVar(&CertAuto, "webhook-cert-auto", false, "Whether to generate the self-signed certificates of the webhook server in the webhook-cert-secret, write them to the webhook-cert-dir and rotate them before expiry")
+	f.StringVar(&CertSecret, "webhook-cert-secret", "chaosblade-webhook-server-cert", "The secret in the chaosblade namespace stores the certificates generated by the operator")
+	f.DurationVar(&CertValidity, "webhook-cert-validity", 365*24*time.Hour, "The validity of the generated ca and serving certificate")
+	f.DurationVar(&CertRotateBefore, "webhook-cert-rotate-before", 30*24*time.Hour, "Rotate the generated certificates the duration before expiry")
+	f.BoolVar(&ManageConfiguration, "webhook-manage-configuration", false, "Whether to create and update the MutatingWebhookConfiguration by the operator")
+	f.StringVar(&ConfigurationName, "webhook-configuration-name", "chaosblade-operator", "The name of the MutatingWebhookConfiguration managed by the operator")
+	f.StringVar(&ServiceName, "webhook-service-name", "chaosblade-webhook-server", "The service of the webhook server in the chaosblade namespace")
+	f.StringVar(&NamespaceSelector, "webhook-namespace-selector", "", "The label selector of the namespaces sent to the webhook, such as env!=production")
+	f.StringVar(&ObjectSelector, "webhook-object-selector", "", "The label selector of the pods sent to the webhook")
+	f.BoolVar(&NamespaceOptIn, "webhook-namespace-opt-in", false, "Only the namespaces labeled with chaosblade.io/fuse-injection=enabled are sent to the webhook")
 }
 
 func FlagSet() *pflag.FlagSet {